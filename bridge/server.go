@@ -0,0 +1,183 @@
+package bridge
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultCardRange is the number of card overlay images in overlays/.
+	defaultCardRange = 67
+	// defaultNumberRange matches a European wheel.
+	defaultNumberRange = 37
+	// maxCount caps how many numbers one request may draw.
+	maxCount = 1024
+)
+
+// Config configures the HTTP bridge.
+type Config struct {
+	Addr    string   // listen address, host:port
+	RngAddr string   // RNG service address, empty for the in-process RNG
+	Origins []string // CORS allowlist, "*" allows any origin
+	APIKey  string   // required on every request when set
+}
+
+// bridge serves RNG draws over plain HTTP for browser overlays.
+type bridge struct {
+	cfg Config
+	rng *rng.RNGClient
+}
+
+// Start boots the HTTP bridge and never returns unless an error occurs.
+func Start(cfg Config) error {
+	var client *rng.RNGClient
+	if cfg.RngAddr != "" {
+		c, err := rng.NewRNGClient(cfg.RngAddr)
+		if err != nil {
+			return err
+		}
+		client = c
+	} else {
+		c, err := rng.NewLocalRNGClient()
+		if err != nil {
+			return err
+		}
+		client = c
+	}
+	defer client.Close()
+
+	b := &bridge{cfg: cfg, rng: client.Stream("bridge", "")}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/random_card", b.guard(b.randomCard))
+	mux.HandleFunc("/api/random_number", b.guard(b.randomNumber))
+
+	source := cfg.RngAddr
+	if source == "" {
+		source = "in-process"
+	}
+	log.Info().
+		Str("addr", cfg.Addr).
+		Str("rng", source).
+		Strs("origins", cfg.Origins).
+		Bool("apiKey", cfg.APIKey != "").
+		Msg("HTTP bridge listening")
+	return http.ListenAndServe(cfg.Addr, mux)
+}
+
+// guard applies CORS and API key checks before h.
+func (b *bridge) guard(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && b.allowOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, Authorization")
+			w.Header().Set("Vary", "Origin")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if !b.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (b *bridge) allowOrigin(origin string) bool {
+	for _, o := range b.cfg.Origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized accepts the key as X-API-Key or a bearer token. Keys in the
+// query string end up in access logs and browser history, so they are ignored.
+func (b *bridge) authorized(r *http.Request) bool {
+	if b.cfg.APIKey == "" {
+		return true
+	}
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	return key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(b.cfg.APIKey)) == 1
+}
+
+// randomCard answers {"card_number": n} with n in [0, range).
+func (b *bridge) randomCard(w http.ResponseWriter, r *http.Request) {
+	rg, ok := queryInt(w, r, "range", defaultCardRange, 1<<31-1)
+	if !ok {
+		return
+	}
+	n, err := b.rng.GetRandomNumber(rg)
+	if err != nil {
+		log.Err(err).Msg("card draw failed")
+		writeError(w, http.StatusInternalServerError, "Failed to get random number")
+		return
+	}
+	writeJSON(w, map[string]uint32{"card_number": n % uint32(rg)})
+}
+
+// randomNumber answers {"numbers": [...]} with count values in [0, range).
+func (b *bridge) randomNumber(w http.ResponseWriter, r *http.Request) {
+	rg, ok := queryInt(w, r, "range", defaultNumberRange, 1<<31-1)
+	if !ok {
+		return
+	}
+	count, ok := queryInt(w, r, "count", 1, maxCount)
+	if !ok {
+		return
+	}
+	nums, err := b.rng.GetRandomNumbers(int32(count), rg)
+	if err != nil {
+		log.Err(err).Msg("number draw failed")
+		writeError(w, http.StatusInternalServerError, "Failed to get random number")
+		return
+	}
+	for i := range nums {
+		nums[i] %= uint32(rg)
+	}
+	writeJSON(w, map[string]interface{}{"numbers": nums, "range": rg})
+}
+
+// queryInt reads a positive integer query parameter no larger than max.
+func queryInt(w http.ResponseWriter, r *http.Request, name string, def, max int) (int, bool) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v <= 0 || v > max {
+		writeError(w, http.StatusBadRequest, "invalid "+name)
+		return 0, false
+	}
+	return v, true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,160 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+)
+
+func newTestBridge(t *testing.T, cfg Config) *bridge {
+	t.Helper()
+	c, err := rng.NewLocalRNGClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return &bridge{cfg: cfg, rng: c.Stream("bridge", "")}
+}
+
+// serve runs one request through the guarded card handler.
+func serve(b *bridge, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	b.guard(b.randomCard)(rec, req)
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allowed bool
+	}{
+		{"listed", []string{"null", "http://localhost:8080"}, "http://localhost:8080", true},
+		{"null origin", []string{"null", "http://localhost:8080"}, "null", true},
+		{"not listed", []string{"http://localhost:8080"}, "http://evil.example", false},
+		{"prefix is not a match", []string{"http://localhost"}, "http://localhost:8080", false},
+		{"wildcard", []string{"*"}, "http://any.example", true},
+		{"empty allowlist", nil, "http://localhost:8080", false},
+	}
+	for _, tt := range tests {
+		b := newTestBridge(t, Config{Origins: tt.origins})
+		for _, method := range []string{http.MethodOptions, http.MethodGet} {
+			rec := serve(b, method, "/api/random_card", map[string]string{"Origin": tt.origin})
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s %s: status = %d", tt.name, method, rec.Code)
+			}
+			got := rec.Header().Get("Access-Control-Allow-Origin")
+			if tt.allowed && got != tt.origin {
+				t.Errorf("%s %s: Access-Control-Allow-Origin = %q, want %q", tt.name, method, got, tt.origin)
+			}
+			if !tt.allowed && got != "" {
+				t.Errorf("%s %s: Access-Control-Allow-Origin = %q, want none", tt.name, method, got)
+			}
+			if tt.allowed && rec.Header().Get("Vary") != "Origin" {
+				t.Errorf("%s %s: missing Vary: Origin", tt.name, method)
+			}
+		}
+	}
+}
+
+func TestGuardMethod(t *testing.T) {
+	b := newTestBridge(t, Config{APIKey: "secret"})
+
+	// preflight carries no key and must not be rejected
+	if rec := serve(b, http.MethodOptions, "/api/random_card", nil); rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("OPTIONS: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		rec := serve(b, method, "/api/random_card", map[string]string{"X-API-Key": "secret"})
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s: status = %d, want %d", method, rec.Code, http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header map[string]string
+		code   int
+	}{
+		{"x-api-key", "/api/random_card", map[string]string{"X-API-Key": "secret"}, http.StatusOK},
+		{"bearer", "/api/random_card", map[string]string{"Authorization": "Bearer secret"}, http.StatusOK},
+		{"missing", "/api/random_card", nil, http.StatusUnauthorized},
+		{"wrong key", "/api/random_card", map[string]string{"X-API-Key": "secre"}, http.StatusUnauthorized},
+		{"wrong bearer", "/api/random_card", map[string]string{"Authorization": "Bearer other"}, http.StatusUnauthorized},
+		{"no bearer scheme", "/api/random_card", map[string]string{"Authorization": "secret"}, http.StatusUnauthorized},
+		{"basic scheme", "/api/random_card", map[string]string{"Authorization": "Basic secret"}, http.StatusUnauthorized},
+		{"query string", "/api/random_card?api_key=secret", nil, http.StatusUnauthorized},
+	}
+	b := newTestBridge(t, Config{APIKey: "secret"})
+	for _, tt := range tests {
+		rec := serve(b, http.MethodGet, tt.target, tt.header)
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.code)
+		}
+		if tt.code == http.StatusUnauthorized {
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] != "invalid api key" {
+				t.Errorf("%s: body = %q", tt.name, rec.Body.String())
+			}
+		}
+	}
+
+	// without a configured key every request passes
+	open := newTestBridge(t, Config{})
+	if rec := serve(open, http.MethodGet, "/api/random_card", nil); rec.Code != http.StatusOK {
+		t.Errorf("no key configured: status = %d", rec.Code)
+	}
+}
+
+func TestRandomNumber(t *testing.T) {
+	b := newTestBridge(t, Config{})
+	tests := []struct {
+		target string
+		code   int
+		count  int
+	}{
+		{"/api/random_number", http.StatusOK, 1},
+		{"/api/random_number?range=6&count=10", http.StatusOK, 10},
+		{"/api/random_number?count=1025", http.StatusBadRequest, 0},
+		{"/api/random_number?range=0", http.StatusBadRequest, 0},
+		{"/api/random_number?range=abc", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		rec := httptest.NewRecorder()
+		b.guard(b.randomNumber)(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.code)
+			continue
+		}
+		if tt.code != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Numbers []uint32 `json:"numbers"`
+			Range   uint32   `json:"range"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if len(body.Numbers) != tt.count {
+			t.Errorf("%s: %d numbers, want %d", tt.target, len(body.Numbers), tt.count)
+		}
+		for _, n := range body.Numbers {
+			if n >= body.Range {
+				t.Errorf("%s: %d outside range %d", tt.target, n, body.Range)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package cheat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gitee.com/heartfun/rouletteserv/game"
+)
+
+// Cheat 解析后的作弊指令, 只在开发环境开启
+type Cheat struct {
+	Pockets []int    // 接下来每一局强制的获胜数字, 第一个用于本局
+	Cards   []uint32 // 接下来发出的牌
+}
+
+// Parse 解析作弊指令, 多条指令用分号分隔:
+//
+//	17          本局开 17, 兼容旧格式, 逗号后的内容被忽略
+//	pocket:17   本局开 17
+//	seq:1,2,3   从本局开始接下来三局依次开 1、2、3
+//	cards:5,12  接下来发出的两张牌依次为 5、12
+func Parse(s string) (*Cheat, error) {
+	c := &Cheat{}
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kind, args, found := strings.Cut(part, ":")
+		if !found {
+			// 旧格式: 只取第一个数字
+			kind, args = "pocket", strings.SplitN(part, ",", 2)[0]
+		}
+		nums, err := parseInts(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", part, err)
+		}
+
+		switch strings.TrimSpace(kind) {
+		case "pocket":
+			if len(nums) != 1 {
+				return nil, fmt.Errorf("%s: want one pocket", part)
+			}
+			fallthrough
+		case "seq":
+			for _, n := range nums {
+				if n < 0 || n >= game.NumberCount {
+					return nil, fmt.Errorf("%s: pocket %d out of range", part, n)
+				}
+			}
+			c.Pockets = nums
+		case "cards":
+			for _, n := range nums {
+				if n < 0 {
+					return nil, fmt.Errorf("%s: invalid card %d", part, n)
+				}
+				c.Cards = append(c.Cards, uint32(n))
+			}
+		default:
+			return nil, fmt.Errorf("unknown cheat %q", kind)
+		}
+	}
+	if len(c.Pockets) == 0 && len(c.Cards) == 0 {
+		return nil, fmt.Errorf("empty cheat")
+	}
+	return c, nil
+}
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", f)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// String 返回规范形式, 用于日志
+func (c *Cheat) String() string {
+	var parts []string
+	if len(c.Pockets) > 0 {
+		parts = append(parts, "seq:"+joinInts(c.Pockets))
+	}
+	if len(c.Cards) > 0 {
+		cards := make([]int, len(c.Cards))
+		for i, v := range c.Cards {
+			cards[i] = int(v)
+		}
+		parts = append(parts, "cards:"+joinInts(cards))
+	}
+	return strings.Join(parts, ";")
+}
+
+func joinInts(nums []int) string {
+	s := make([]string, len(nums))
+	for i, n := range nums {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
+
+// Queue 尚未用掉的强制结果, 并发安全
+type Queue struct {
+	mu      sync.Mutex
+	pockets []int
+	cards   []uint32
+}
+
+// Push 排入 c 的强制结果, 新指令替换尚未用掉的同类结果
+func (q *Queue) Push(c *Cheat) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(c.Pockets) > 0 {
+		q.pockets = append([]int(nil), c.Pockets...)
+	}
+	if len(c.Cards) > 0 {
+		q.cards = append([]uint32(nil), c.Cards...)
+	}
+}
+
+// NextPocket 取出本局强制的获胜数字, left 为之后还剩的局数
+func (q *Queue) NextPocket() (pocket, left int, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pockets) == 0 {
+		return 0, 0, false
+	}
+	pocket, q.pockets = q.pockets[0], q.pockets[1:]
+	return pocket, len(q.pockets), true
+}
+
+// NextCard 取出下一张强制的牌, left 为之后还剩的张数
+func (q *Queue) NextCard() (card uint32, left int, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.cards) == 0 {
+		return 0, 0, false
+	}
+	card, q.cards = q.cards[0], q.cards[1:]
+	return card, len(q.cards), true
+}
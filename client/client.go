@@ -60,6 +60,10 @@ func main() {
 	}
 	// 打印结果
 	fmt.Printf("Winning number: %d\n", curGameModParam.WinningNumber)
+	if w := curGameModParam.Wheel; w != nil {
+		fmt.Printf("Wheel: pocket index %d, %d ball revolutions, %.1fs, landing at %.1f°\n",
+			w.PocketIndex, w.BallRevolutions, w.Duration, w.LandingAngle)
+	}
 	fmt.Printf("Total win: %d\n", resp.Results[0].CashWin)
 	fmt.Println("Bet results:")
 	for _, win := range curGameModParam.Wins {
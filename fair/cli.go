@@ -0,0 +1,84 @@
+package fair
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// VerifyCommand 重新计算一局 provably fair 的结果
+//
+// replyPath 为 Play2 返回的 ReplyPlay (protojson), 从中读取 clientSeed、nonce、
+// 种子承诺值和获胜数字; 否则使用 clientSeed 和 nonce 参数.
+// serverSeed 为空时通过 rouletteAddr 的 Fair 服务查询已公开的种子.
+func VerifyCommand(replyPath, serverSeed, clientSeed string, nonce uint64, rouletteAddr string) error {
+	hash := ""
+	expected := -1
+
+	if replyPath != "" {
+		data, err := os.ReadFile(replyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read reply: %v", err)
+		}
+		var reply proto.ReplyPlay
+		if err := protojson.Unmarshal(data, &reply); err != nil {
+			return fmt.Errorf("invalid reply: %v", err)
+		}
+		if len(reply.Results) == 0 || reply.Results[0].ClientData == nil {
+			return fmt.Errorf("reply has no result")
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+			return fmt.Errorf("invalid game mod param: %v", err)
+		}
+		if gmp.Fair == nil {
+			return fmt.Errorf("round was not played in provably fair mode")
+		}
+		hash = gmp.Fair.ServerSeedHash
+		clientSeed = gmp.Fair.ClientSeed
+		nonce = gmp.Fair.Nonce
+		expected = int(gmp.WinningNumber)
+	}
+
+	if serverSeed == "" {
+		if hash == "" || rouletteAddr == "" {
+			return fmt.Errorf("need a server seed, or a reply and a roulette address to reveal it")
+		}
+		conn, err := grpc.Dial(rouletteAddr, grpc.WithInsecure())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		seed, err := proto.NewFairClient(conn).RevealSeed(ctx, &proto.RequestRevealSeed{ServerSeedHash: hash})
+		if err != nil {
+			return fmt.Errorf("failed to reveal server seed: %v", err)
+		}
+		serverSeed = seed.ServerSeed
+	}
+
+	if hash != "" && HashSeed(serverSeed) != hash {
+		return fmt.Errorf("server seed does not match commitment %s", hash)
+	}
+
+	number := Outcome(serverSeed, clientSeed, nonce, game.NumberCount)
+	log.Info().
+		Str("serverSeedHash", HashSeed(serverSeed)).
+		Str("clientSeed", clientSeed).
+		Uint64("nonce", nonce).
+		Int("winningNumber", number).
+		Msg("provably fair outcome")
+
+	if expected >= 0 && number != expected {
+		return fmt.Errorf("mismatch: recorded winning number %d, recomputed %d", expected, number)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package fair
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+)
+
+// Seed 服务端种子
+type Seed struct {
+	Seed       string     `json:"seed"`
+	Hash       string     `json:"hash"`  // sha256(Seed), 开局前公布
+	Nonce      uint64     `json:"nonce"` // 下一局使用的 nonce, 即已玩局数
+	CreatedAt  time.Time  `json:"createdAt"`
+	RevealedAt *time.Time `json:"revealedAt,omitempty"`
+}
+
+// Draw 一局的 provably fair 结果
+type Draw struct {
+	Number     int
+	Hash       string
+	ClientSeed string
+	Nonce      uint64
+	Raw        uint32   // 被采用的 32 位数, Number = Raw % r
+	Rejected   []uint32 // 在 Raw 之前被拒绝的 32 位数
+}
+
+// Manager 管理当前种子和已公开的旧种子
+type Manager struct {
+	mu          sync.Mutex
+	path        string // 种子持久化文件, 为空时只保存在内存
+	rotateEvery uint64 // 每多少局自动换种子, 0 不自动换
+	cur         *Seed
+	retired     map[string]*Seed
+}
+
+// seedFile 持久化文件格式
+type seedFile struct {
+	Current *Seed   `json:"current"`
+	Retired []*Seed `json:"retired"`
+}
+
+// HashSeed 返回种子的承诺值
+func HashSeed(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Outcome 由种子和 nonce 确定结果, 范围 [0, r)
+//
+// 取 HMAC-SHA256(serverSeed, "clientSeed:nonce:cursor") 的每 4 个字节作为
+// 大端 32 位数, 用与 RNG 服务相同的拒绝采样无偏缩放; 一个摘要全部被拒绝时
+// cursor 加一重新计算.
+func Outcome(serverSeed, clientSeed string, nonce uint64, r int) int {
+	number, _, _ := OutcomeDraw(serverSeed, clientSeed, nonce, r)
+	return number
+}
+
+// OutcomeDraw 与 Outcome 相同, 同时返回被采用和被拒绝的 32 位数
+func OutcomeDraw(serverSeed, clientSeed string, nonce uint64, r int) (number int, raw uint32, rejected []uint32) {
+	for cursor := 0; ; cursor++ {
+		mac := hmac.New(sha256.New, []byte(serverSeed))
+		fmt.Fprintf(mac, "%s:%d:%d", clientSeed, nonce, cursor)
+		sum := mac.Sum(nil)
+		for i := 0; i+4 <= len(sum); i += 4 {
+			raw = binary.BigEndian.Uint32(sum[i : i+4])
+			if v, ok := rng.ScaleUniform(raw, uint32(r)); ok {
+				return int(v), raw, rejected
+			}
+			rejected = append(rejected, raw)
+		}
+	}
+}
+
+// NewManager 创建种子管理器, path 存在时从中恢复种子
+func NewManager(path string, rotateEvery uint64) (*Manager, error) {
+	m := &Manager{
+		path:        path,
+		rotateEvery: rotateEvery,
+		retired:     make(map[string]*Seed),
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var f seedFile
+			if err := json.Unmarshal(data, &f); err != nil {
+				return nil, fmt.Errorf("invalid seed file %s: %v", path, err)
+			}
+			m.cur = f.Current
+			for _, s := range f.Retired {
+				m.retired[s.Hash] = s
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read seed file: %v", err)
+		}
+	}
+
+	if m.cur == nil {
+		seed, err := newSeed()
+		if err != nil {
+			return nil, err
+		}
+		m.cur = seed
+		if err := m.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func newSeed() (*Seed, error) {
+	var buf [32]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate server seed: %v", err)
+	}
+	seed := hex.EncodeToString(buf[:])
+	return &Seed{
+		Seed:      seed,
+		Hash:      HashSeed(seed),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Commitment 返回当前种子的承诺值和下一局的 nonce
+func (m *Manager) Commitment() (string, uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cur.Hash, m.cur.Nonce
+}
+
+// Draw 用当前种子开一局
+// nonce 在返回前落盘, 崩溃后也不会被重复使用
+func (m *Manager) Draw(clientSeed string, r int) (*Draw, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rotateEvery > 0 && m.cur.Nonce >= m.rotateEvery {
+		if _, err := m.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &Draw{
+		Hash:       m.cur.Hash,
+		ClientSeed: clientSeed,
+		Nonce:      m.cur.Nonce,
+	}
+	m.cur.Nonce++
+	if err := m.save(); err != nil {
+		m.cur.Nonce--
+		return nil, err
+	}
+	d.Number, d.Raw, d.Rejected = OutcomeDraw(m.cur.Seed, clientSeed, d.Nonce, r)
+	return d, nil
+}
+
+// Rotate 公开当前种子并换成新种子, 返回被公开的种子
+func (m *Manager) Rotate() (*Seed, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, err := m.rotate()
+	if err != nil {
+		return nil, "", err
+	}
+	return old, m.cur.Hash, nil
+}
+
+// rotate 先把换种子后的状态落盘再切换, 落盘失败时继续使用当前种子且不公开它
+func (m *Manager) rotate() (*Seed, error) {
+	next, err := newSeed()
+	if err != nil {
+		return nil, err
+	}
+	old := *m.cur
+	now := time.Now()
+	old.RevealedAt = &now
+	if err := m.write(next, append(m.retiredSeeds(), &old)); err != nil {
+		return nil, err
+	}
+	m.retired[old.Hash] = &old
+	m.cur = next
+	return &old, nil
+}
+
+// Reveal 返回已公开的旧种子, 当前种子在换掉之前不会公开
+func (m *Manager) Reveal(hash string) (*Seed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if hash == m.cur.Hash {
+		return nil, fmt.Errorf("server seed %s is still in use, rotate it first", hash)
+	}
+	s, ok := m.retired[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown server seed %s", hash)
+	}
+	cp := *s
+	return &cp, nil
+}
+
+// save 原子地写入种子文件, 调用方持有锁
+func (m *Manager) save() error {
+	return m.write(m.cur, m.retiredSeeds())
+}
+
+func (m *Manager) retiredSeeds() []*Seed {
+	out := make([]*Seed, 0, len(m.retired)+1)
+	for _, s := range m.retired {
+		out = append(out, s)
+	}
+	return out
+}
+
+// write 写入给定的当前种子和已公开的种子, 先写临时文件再改名
+func (m *Manager) write(cur *Seed, retired []*Seed) error {
+	if m.path == "" {
+		return nil
+	}
+	f := seedFile{Current: cur, Retired: retired}
+	sort.Slice(f.Retired, func(i, j int) bool { return f.Retired[i].CreatedAt.Before(f.Retired[j].CreatedAt) })
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write seed file: %v", err)
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		return fmt.Errorf("failed to write seed file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package fair
+
+import (
+	"context"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+)
+
+// Service provably fair 的 gRPC 服务
+type Service struct {
+	proto.UnimplementedFairServer
+	m *Manager
+}
+
+// NewService 创建 provably fair 服务
+func NewService(m *Manager) *Service {
+	return &Service{m: m}
+}
+
+// Commitment 当前种子的承诺值
+func (s *Service) Commitment(ctx context.Context, req *proto.RequestCommitment) (*proto.ReplyCommitment, error) {
+	hash, nonce := s.m.Commitment()
+	return &proto.ReplyCommitment{
+		ServerSeedHash: hash,
+		Nonce:          nonce,
+	}, nil
+}
+
+// AdminService provably fair 的运维 gRPC 服务, 只在运维端口上提供
+type AdminService struct {
+	proto.UnimplementedFairAdminServer
+	m *Manager
+}
+
+// NewAdminService 创建 provably fair 运维服务
+func NewAdminService(m *Manager) *AdminService {
+	return &AdminService{m: m}
+}
+
+// RotateSeed 公开当前种子并换新种子
+func (s *AdminService) RotateSeed(ctx context.Context, req *proto.RequestRotateSeed) (*proto.ReplyRotateSeed, error) {
+	old, next, err := s.m.Rotate()
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReplyRotateSeed{
+		ServerSeed:         old.Seed,
+		ServerSeedHash:     old.Hash,
+		Rounds:             old.Nonce,
+		NextServerSeedHash: next,
+	}, nil
+}
+
+// RevealSeed 查询已公开的旧种子
+func (s *Service) RevealSeed(ctx context.Context, req *proto.RequestRevealSeed) (*proto.ReplyRevealSeed, error) {
+	seed, err := s.m.Reveal(req.ServerSeedHash)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReplyRevealSeed{
+		ServerSeed:     seed.Seed,
+		ServerSeedHash: seed.Hash,
+		Rounds:         seed.Nonce,
+	}, nil
+}
+
+// Verify 重新计算一局的获胜数字
+// 没有给出 serverSeed 时按 serverSeedHash 查找已公开的种子;
+// 没有给出 serverSeedHash 时 hashMatches 恒为 true
+func (s *Service) Verify(ctx context.Context, req *proto.RequestVerify) (*proto.ReplyVerify, error) {
+	seed := req.ServerSeed
+	if seed == "" {
+		revealed, err := s.m.Reveal(req.ServerSeedHash)
+		if err != nil {
+			return nil, err
+		}
+		seed = revealed.Seed
+	}
+	return &proto.ReplyVerify{
+		WinningNumber: int32(Outcome(seed, req.ClientSeed, req.Nonce, game.NumberCount)),
+		ServerSeed:    seed,
+		HashMatches:   req.ServerSeedHash == "" || HashSeed(seed) == req.ServerSeedHash,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	crand "crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Clip 一段预录的开奖视频
+type Clip struct {
+	ID       string  `json:"id"`
+	Pocket   int     `json:"pocket"`   // 球最终落入的数字
+	Variant  string  `json:"variant"`  // 机位/轮盘等版本, 空为默认
+	File     string  `json:"file"`     // 播放器使用的视频地址
+	Duration float64 `json:"duration"` // 视频总长, 秒
+	ResultAt float64 `json:"resultAt"` // 球落定的时间点, overlay 在此时显示结果
+}
+
+// ClipLibrary 按数字和版本索引的视频库, 每个数字可有多个take
+type ClipLibrary struct {
+	Variant string // 默认版本
+	takes   map[string]map[int][]*Clip
+}
+
+// clipManifest 视频库清单文件格式
+type clipManifest struct {
+	Variant string  `json:"variant"`
+	Clips   []*Clip `json:"clips"`
+}
+
+// LoadClipLibrary 从 JSON 清单加载视频库
+func LoadClipLibrary(path string) (*ClipLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clip manifest: %v", err)
+	}
+
+	var m clipManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid clip manifest: %v", err)
+	}
+
+	return NewClipLibrary(m.Variant, m.Clips)
+}
+
+// NewClipLibrary 创建视频库, 检查每个版本是否覆盖全部数字
+func NewClipLibrary(variant string, clips []*Clip) (*ClipLibrary, error) {
+	lib := &ClipLibrary{
+		Variant: variant,
+		takes:   make(map[string]map[int][]*Clip),
+	}
+
+	ids := make(map[string]bool, len(clips))
+	for _, c := range clips {
+		if c.ID == "" {
+			return nil, fmt.Errorf("clip without id for pocket %d", c.Pocket)
+		}
+		if ids[c.ID] {
+			return nil, fmt.Errorf("duplicate clip id %s", c.ID)
+		}
+		ids[c.ID] = true
+		if c.Pocket < 0 || c.Pocket >= NumberCount {
+			return nil, fmt.Errorf("clip %s: invalid pocket %d", c.ID, c.Pocket)
+		}
+		if c.ResultAt < 0 || (c.Duration > 0 && c.ResultAt > c.Duration) {
+			return nil, fmt.Errorf("clip %s: resultAt %.2f outside clip", c.ID, c.ResultAt)
+		}
+
+		byPocket, ok := lib.takes[c.Variant]
+		if !ok {
+			byPocket = make(map[int][]*Clip)
+			lib.takes[c.Variant] = byPocket
+		}
+		byPocket[c.Pocket] = append(byPocket[c.Pocket], c)
+	}
+
+	if _, ok := lib.takes[variant]; !ok {
+		return nil, fmt.Errorf("default variant %q has no clips", variant)
+	}
+
+	for v, byPocket := range lib.takes {
+		for n := 0; n < NumberCount; n++ {
+			if len(byPocket[n]) == 0 {
+				log.Warn().Str("variant", v).Int("pocket", n).Msg("no clip for pocket")
+			}
+		}
+		// 固定 take 顺序, 同一随机数总是选中同一段视频
+		for _, t := range byPocket {
+			sort.Slice(t, func(i, j int) bool { return t[i].ID < t[j].ID })
+		}
+	}
+
+	return lib, nil
+}
+
+// HasVariant 判断视频库中是否有该版本
+func (l *ClipLibrary) HasVariant(variant string) bool {
+	_, ok := l.takes[variant]
+	return ok
+}
+
+// Pick 在结果确定后为该数字均匀随机选择一个 take
+// variant 为空或没有该数字的视频时使用默认版本
+func (l *ClipLibrary) Pick(pocket int, variant string, rngClient RNGClient) (*Clip, error) {
+	if variant == "" {
+		variant = l.Variant
+	}
+	takes := l.takes[variant][pocket]
+	if len(takes) == 0 {
+		takes = l.takes[l.Variant][pocket]
+	}
+	if len(takes) == 0 {
+		return nil, fmt.Errorf("no clip for pocket %d", pocket)
+	}
+	if len(takes) == 1 {
+		return takes[0], nil
+	}
+
+	if rngClient != nil {
+		num, err := rngClient.GetRandomNumber(len(takes))
+		if err != nil {
+			return nil, err
+		}
+		return takes[int(num%uint32(len(takes)))], nil
+	}
+
+	n, err := crand.Int(crand.Reader, big.NewInt(int64(len(takes))))
+	if err != nil {
+		return nil, err
+	}
+	return takes[n.Int64()], nil
+}
@@ -10,8 +10,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// 欧洲轮盘数字 (0-36)
-// var wheelNumbers = []int{0, 32, 15, 19, 4, 21, 2, 25, 17, 34, 6, 27, 13, 36, 11, 30, 8, 23, 10, 5, 24, 16, 33, 1, 20, 14, 31, 9, 22, 18, 29, 7, 28, 12, 35, 3, 26}
+// 欧洲轮盘数字 (0-36), 按轮盘上顺时针的物理顺序
+var wheelNumbers = []int{0, 32, 15, 19, 4, 21, 2, 25, 17, 34, 6, 27, 13, 36, 11, 30, 8, 23, 10, 5, 24, 16, 33, 1, 20, 14, 31, 9, 22, 18, 29, 7, 28, 12, 35, 3, 26}
+
 const (
 	NumberCount = 37
 )
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"fmt"
 	"runtime"
 	"sync"
 
@@ -8,18 +9,19 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func CalculateRTP(numRounds int, rngAddr string) float64 {
+// CalculateRTP 模拟 numRounds 局并返回整体RTP
+// 任一随机数请求失败时返回错误, 不返回只统计了部分局的结果
+func CalculateRTP(numRounds int, rngAddr string) (float64, error) {
 	var rngClient RNGClient
 
-	// 如果有RNG服务地址，则创建RNG客户端
+	// 如果有RNG服务地址，则创建RNG客户端, 失败时不回退到本地随机数
 	if rngAddr != "" {
 		client, err := rng.NewRNGClient(rngAddr)
 		if err != nil {
-			log.Err(err).Msg("failed to create RNG client")
-		} else {
-			defer client.Close()
-			rngClient = client
+			return 0, fmt.Errorf("failed to create RNG client: %v", err)
 		}
+		defer client.Close()
+		rngClient = client
 	}
 
 	roulette := NewRoulette(rngClient)
@@ -56,19 +58,39 @@ func CalculateRTP(numRounds int, rngAddr string) float64 {
 		win int64
 	}, maxWorkers*10)
 
+	// 第一个错误; 出错后其余任务直接跳过, 分发任务的 goroutine 不会阻塞
+	var errMu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		errMu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		errMu.Unlock()
+	}
+	failed := func() bool {
+		errMu.Lock()
+		defer errMu.Unlock()
+		return firstErr != nil
+	}
+
 	var wg sync.WaitGroup
 	for i := 0; i < maxWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+		tasks:
 			for task := range taskChan {
+				if failed() {
+					continue
+				}
 				// 每个任务创建独立实例
 				localRoulette := NewRoulette(rngClient)
 
 				betType, err := DetermineBetType(task.bt.numbers)
 				if err != nil {
-					log.Err(err).Msg("DetermineBetType() error")
-					return
+					fail(fmt.Errorf("DetermineBetType(%v): %v", task.bt.numbers, err))
+					continue
 				}
 
 				// 计算理论RTP
@@ -90,11 +112,19 @@ func CalculateRTP(numRounds int, rngAddr string) float64 {
 					rngs := make([]uint32, batchSize)
 					if localRoulette.rngClient == nil {
 						for i := 0; i < batchSize; i++ {
-							winningNumber, _ := roulette.Spin()
+							winningNumber, err := roulette.Spin()
+							if err != nil {
+								fail(fmt.Errorf("Spin(): %v", err))
+								continue tasks
+							}
 							rngs[i] = uint32(winningNumber)
 						}
 					} else {
-						nums, _ := localRoulette.rngClient.GetRandomNumbers(int32(batchSize+256), NumberCount)
+						nums, err := localRoulette.rngClient.GetRandomNumbers(int32(batchSize), NumberCount)
+						if err != nil {
+							fail(fmt.Errorf("GetRandomNumbers(): %v", err))
+							continue tasks
+						}
 						rngs = nums
 					}
 					for _, rng := range rngs {
@@ -114,8 +144,8 @@ func CalculateRTP(numRounds int, rngAddr string) float64 {
 					// 旋转轮盘
 					winningNumber, err := roulette.Spin()
 					if err != nil {
-						log.Err(err).Msg("Spin() error")
-						continue
+						fail(fmt.Errorf("Spin(): %v", err))
+						continue tasks
 					}
 
 					// 检查是否获胜
@@ -186,8 +216,15 @@ func CalculateRTP(numRounds int, rngAddr string) float64 {
 		sumWin += res.win
 	}
 
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	if sumBet == 0 {
+		return 0, fmt.Errorf("no rounds simulated, need at least %d", len(betTypes))
+	}
+
 	// 计算整体RTP
 	overallRTP := float64(sumWin) / float64(sumBet)
 	log.Info().Msgf("Overall RTP = %.2f%% (after %d rounds) totalWagered=%d, totalWon=%d", overallRTP*100, numRounds, sumBet, sumWin)
-	return overallRTP
+	return overallRTP, nil
 }
@@ -0,0 +1,125 @@
+package game
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// 动画参数取值范围, 角度单位 0.1 度, 时间单位 0.1 秒
+const (
+	rotorSpeedMin    = 200 // 20 度/秒
+	rotorSpeedSpan   = 201 // 到 40 度/秒
+	spinDurationMin  = 70  // 7 秒
+	spinDurationSpan = 31  // 到 10 秒
+	ballRevsMin      = 8
+	ballRevsSpan     = 5 // 到 12 圈
+)
+
+// pocketArc 每个格子所占角度
+const pocketArc = 360.0 / NumberCount
+
+// WheelAnimation 轮盘动画参数
+//
+// 转子顺时针转动, 球逆时针转动; 所有角度从台面的 0 度开始顺时针计算.
+// 落定时 LandingAngle = RotorStartAngle + RotorSpeed*Duration + PocketAngle,
+// 球共转过 BallRevolutions 圈再加上从 BallStartAngle 到 LandingAngle 的余量.
+type WheelAnimation struct {
+	PocketIndex     int
+	RotorStartAngle float64
+	RotorSpeed      float64
+	BallStartAngle  float64
+	BallRevolutions int
+	Duration        float64
+	PocketAngle     float64
+	LandingAngle    float64
+}
+
+// PocketIndex 返回数字在轮盘物理顺序中的位置
+func PocketIndex(number int) (int, error) {
+	for i, n := range wheelNumbers {
+		if n == number {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid number %d, must be between 0 and 36", number)
+}
+
+// Animate 在结果确定后用RNG生成该局的动画参数
+// 抽取顺序固定, 相同的随机数序列总是得到相同的动画
+func (r *Roulette) Animate(winningNumber int) (*WheelAnimation, error) {
+	idx, err := PocketIndex(winningNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	vals, err := r.randInts([]int{3600, rotorSpeedSpan, spinDurationSpan, ballRevsSpan, 3600})
+	if err != nil {
+		return nil, err
+	}
+
+	a := &WheelAnimation{
+		PocketIndex:     idx,
+		RotorStartAngle: float64(vals[0]) / 10,
+		RotorSpeed:      float64(rotorSpeedMin+vals[1]) / 10,
+		Duration:        float64(spinDurationMin+vals[2]) / 10,
+		BallRevolutions: ballRevsMin + vals[3],
+		BallStartAngle:  float64(vals[4]) / 10,
+		PocketAngle:     float64(idx) * pocketArc,
+	}
+	a.LandingAngle = math.Mod(a.RotorStartAngle+a.RotorSpeed*a.Duration+a.PocketAngle, 360)
+
+	return a, nil
+}
+
+// rawSource 可以一次取多个原始 32 位随机数的RNG客户端
+type rawSource interface {
+	GetRawNumbers(nums int32) ([]uint32, error)
+}
+
+// randInts 依次返回 [0, ranges[i]) 的随机整数
+// RNG客户端支持时一次取出所有原始随机数, 再逐个无偏缩放, 只有被拒绝时才补取
+func (r *Roulette) randInts(ranges []int) ([]int, error) {
+	vals := make([]int, len(ranges))
+	raw, ok := r.rngClient.(rawSource)
+	if !ok {
+		for i, n := range ranges {
+			var err error
+			if vals[i], err = r.randInt(n); err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	}
+
+	pending, err := raw.GetRawNumbers(int32(len(ranges)))
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range ranges {
+		var v uint32
+		if v, pending, err = r.rngClient.ScalingRandom(pending, n); err != nil {
+			return nil, err
+		}
+		vals[i] = int(v % uint32(n))
+	}
+	return vals, nil
+}
+
+// randInt 返回 [0, n) 的随机整数, 有RNG服务时使用RNG服务
+func (r *Roulette) randInt(n int) (int, error) {
+	if r.rngClient != nil {
+		num, err := r.rngClient.GetRandomNumber(n)
+		if err != nil {
+			return 0, err
+		}
+		return int(num % uint32(n)), nil
+	}
+
+	v, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	crand "crypto/rand"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/cheat"
+	"gitee.com/heartfun/rouletteserv/game"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultCardRange is the number of card overlay images in overlays/.
+const defaultCardRange = 67
+
+// cardDealer draws overlay cards and announces them on the hub.
+type cardDealer struct {
+	rng    game.RNGClient // nil means local crypto/rand
+	h      *hub
+	cheats *cheat.Queue // nil unless cheats are enabled
+}
+
+// forced returns the next cheated card, if one is queued and fits in [0, r).
+func (d *cardDealer) forced(r int) (uint32, bool) {
+	if d.cheats == nil {
+		return 0, false
+	}
+	card, left, ok := d.cheats.NextCard()
+	if !ok {
+		return 0, false
+	}
+	if card >= uint32(r) {
+		log.Warn().Uint32("card", card).Int("range", r).Msg("cheated card out of range, drawing normally")
+		return 0, false
+	}
+	log.Warn().Uint32("card", card).Int("left", left).Msg("cheated card")
+	return card, true
+}
+
+// draw returns a card index in [0, r).
+func (d *cardDealer) draw(r int) (uint32, error) {
+	if d.rng != nil {
+		n, err := d.rng.GetRandomNumber(r)
+		if err != nil {
+			return 0, err
+		}
+		return n % uint32(r), nil
+	}
+
+	n, err := crand.Int(crand.Reader, big.NewInt(int64(r)))
+	if err != nil {
+		return 0, err
+	}
+	return uint32(n.Int64()), nil
+}
+
+// ServeHTTP answers /api/random_card and pushes the draw to every overlay.
+func (d *cardDealer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	rng := defaultCardRange
+	if s := r.URL.Query().Get("range"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			http.Error(w, `{"error": "invalid range"}`, http.StatusBadRequest)
+			return
+		}
+		rng = n
+	}
+
+	card, cheated := d.forced(rng)
+	if !cheated {
+		var err error
+		if card, err = d.draw(rng); err != nil {
+			log.Err(err).Msg("card draw failed")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "Failed to get random number"}`))
+			return
+		}
+	}
+
+	msg := map[string]interface{}{
+		"type":        "card",
+		"card_number": card,
+		"range":       rng,
+		"ts":          time.Now().UnixMilli(),
+	}
+	if cheated {
+		msg["cheated"] = true
+	}
+	d.h.broadcast(msg)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]uint32{"card_number": card})
+}
@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"gitee.com/heartfun/rouletteserv/cheat"
+)
+
+// registerCheat exposes the development-only cheat endpoint. It is only
+// registered when the gateway runs with cheats enabled.
+//
+//	GET|POST /api/cheat?cheat=pocket:17        force the next spin
+//	GET|POST /api/cheat?cheat=seq:1,2,3        force the next three spins
+//	GET|POST /api/cheat?cheat=cards:5,12       force the next overlay cards
+//
+// Several cheats are separated by ';', which must be escaped as %3B in a
+// query string; a POSTed form needs no escaping.
+//
+// Pocket cheats are forwarded with the next Play2 call and applied by the
+// roulette service, which must run with cheats enabled as well; card cheats
+// are queued on the dealer.
+func registerCheat(mux *http.ServeMux, rm *roundMgr, dealer *cardDealer) {
+	mux.HandleFunc("/api/cheat", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		c, err := cheat.Parse(r.FormValue("cheat"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if len(c.Pockets) > 0 {
+			rm.setCheat((&cheat.Cheat{Pockets: c.Pockets}).String())
+		}
+		if len(c.Cards) > 0 {
+			dealer.cheats.Push(&cheat.Cheat{Cards: c.Cards})
+		}
+		log.Warn().Str("cheat", c.String()).Str("from", r.RemoteAddr).Msg("cheat queued")
+		json.NewEncoder(w).Encode(map[string]string{"queued": c.String()})
+	})
+}
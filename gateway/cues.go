@@ -0,0 +1,315 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lastCueLen is how long the final cue of a session lasts when no later
+// event closes it.
+const lastCueLen = time.Second
+
+// maxCueSessions is how many sessions are kept for the cue endpoints; older
+// ones are dropped from memory once they have been saved.
+const maxCueSessions = 100
+
+// cueSessionID is what a session ID may look like. IDs become file names in
+// the cue directory, so nothing that could leave it is accepted.
+var cueSessionID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// errCueSessionExists is returned when a given session ID is already in use,
+// in memory or by files in the cue directory.
+var errCueSessionExists = errors.New("session id already used")
+
+// cue is one timed game event on the video timeline.
+type cue struct {
+	ID    int                    `json:"id"`
+	Start float64                `json:"start"` // seconds since stream start
+	End   float64                `json:"end"`
+	Kind  string                 `json:"kind"` // phase, result or card
+	Round int64                  `json:"round,omitempty"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// cueSession is every cue recorded between two stream starts.
+type cueSession struct {
+	ID        string    `json:"session"`
+	StartedAt time.Time `json:"startedAt"`
+	Offset    float64   `json:"offset"` // video time at StartedAt, seconds
+	Cues      []*cue    `json:"cues"`
+
+	ends []float64 // explicit cue ends from "duration", 0 when unknown
+}
+
+// cueRecorder listens to hub broadcasts and keeps a cue sheet per session.
+type cueRecorder struct {
+	dir string // optional directory the finished sessions are written to
+
+	mu       sync.Mutex
+	cur      *cueSession
+	sessions map[string]*cueSession
+	order    []string // session IDs, oldest first
+}
+
+func newCueRecorder(dir string) *cueRecorder {
+	cr := &cueRecorder{
+		dir:      dir,
+		sessions: make(map[string]*cueSession),
+	}
+	cr.start("", 0)
+	return cr
+}
+
+// start closes the current session and opens a new one whose time zero is
+// now. offset is the video time already elapsed when the stream started.
+// An empty id is replaced by the start time, with a -2, -3, ... suffix if
+// that is taken; any other id must match cueSessionID and must not be taken.
+func (cr *cueRecorder) start(id string, offset float64) (*cueSession, error) {
+	now := time.Now()
+	auto := id == ""
+	if auto {
+		id = now.Format("20060102T150405")
+	}
+	if !cueSessionID.MatchString(id) {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	cr.mu.Lock()
+	if auto {
+		base := id
+		for n := 2; cr.taken(id); n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+	} else if cr.taken(id) {
+		cr.mu.Unlock()
+		return nil, errCueSessionExists
+	}
+	prev := cr.cur
+	s := &cueSession{ID: id, StartedAt: now, Offset: offset}
+	cr.cur = s
+	cr.order = append(cr.order, id)
+	cr.sessions[id] = s
+	for len(cr.order) > maxCueSessions {
+		delete(cr.sessions, cr.order[0])
+		cr.order = cr.order[1:]
+	}
+	cr.mu.Unlock()
+
+	if prev != nil {
+		cr.save(prev)
+	}
+	return s, nil
+}
+
+// taken reports whether a session ID is kept in memory or has saved files.
+// Callers hold cr.mu.
+func (cr *cueRecorder) taken(id string) bool {
+	if _, ok := cr.sessions[id]; ok {
+		return true
+	}
+	if cr.dir == "" {
+		return false
+	}
+	for _, ext := range []string{".vtt", ".json"} {
+		if _, err := os.Stat(filepath.Join(cr.dir, id+ext)); !errors.Is(err, os.ErrNotExist) {
+			return true
+		}
+	}
+	return false
+}
+
+// observe is a hub observer; it turns phase, result and card broadcasts
+// into cues.
+func (cr *cueRecorder) observe(data []byte) {
+	var ev map[string]interface{}
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return
+	}
+
+	c := &cue{Data: ev}
+	switch ev["type"] {
+	case "state":
+		c.Kind = "phase"
+		if ev["value"] == string(phaseResult) {
+			c.Kind = "result"
+			// the full ReplyPlay is far too large for a cue payload
+			delete(ev, "data")
+		}
+	case "card":
+		c.Kind = "card"
+	default:
+		return
+	}
+	if r, ok := ev["round"].(float64); ok {
+		c.Round = int64(r)
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	s := cr.cur
+	c.ID = len(s.Cues) + 1
+	c.Start = s.Offset + time.Since(s.StartedAt).Seconds()
+	end := 0.0
+	if d, ok := ev["duration"].(float64); ok && d > 0 {
+		end = c.Start + d
+	}
+	s.Cues = append(s.Cues, c)
+	s.ends = append(s.ends, end)
+}
+
+// snapshot returns a resolved copy of a session, the current one when id
+// is empty.
+func (cr *cueRecorder) snapshot(id string) (*cueSession, bool) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	s := cr.cur
+	if id != "" {
+		var ok bool
+		if s, ok = cr.sessions[id]; !ok {
+			return nil, false
+		}
+	}
+	return s.resolved(), true
+}
+
+// resolved copies the session with every cue end filled in: an explicit
+// phase duration, else the next cue's start. Callers hold cueRecorder.mu.
+func (s *cueSession) resolved() *cueSession {
+	out := &cueSession{
+		ID:        s.ID,
+		StartedAt: s.StartedAt,
+		Offset:    s.Offset,
+		Cues:      make([]*cue, len(s.Cues)),
+	}
+	for i, c := range s.Cues {
+		cc := *c
+		switch {
+		case s.ends[i] > 0:
+			cc.End = s.ends[i]
+		case i+1 < len(s.Cues):
+			cc.End = s.Cues[i+1].Start
+		default:
+			cc.End = cc.Start + lastCueLen.Seconds()
+		}
+		if cc.End <= cc.Start {
+			cc.End = cc.Start + 0.001
+		}
+		out.Cues[i] = &cc
+	}
+	return out
+}
+
+// save writes a session's WebVTT and JSON exports into cr.dir.
+func (cr *cueRecorder) save(s *cueSession) {
+	if cr.dir == "" {
+		return
+	}
+	cr.mu.Lock()
+	snap := s.resolved()
+	cr.mu.Unlock()
+	if len(snap.Cues) == 0 {
+		return
+	}
+	if err := os.MkdirAll(cr.dir, 0o755); err != nil {
+		log.Err(err).Str("dir", cr.dir).Msg("failed to create cue directory")
+		return
+	}
+	base := filepath.Join(cr.dir, snap.ID)
+	if err := os.WriteFile(base+".vtt", []byte(snap.webVTT()), 0o644); err != nil {
+		log.Err(err).Str("session", snap.ID).Msg("failed to write WebVTT cues")
+	}
+	j, _ := json.MarshalIndent(snap, "", "  ")
+	if err := os.WriteFile(base+".json", j, 0o644); err != nil {
+		log.Err(err).Str("session", snap.ID).Msg("failed to write JSON cues")
+	}
+}
+
+// webVTT renders the session as a metadata track, one JSON payload per cue.
+func (s *cueSession) webVTT() string {
+	var b strings.Builder
+	b.WriteString("WEBVTT - roulette game events\n\n")
+	for _, c := range s.Cues {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"kind":  c.Kind,
+			"round": c.Round,
+			"data":  c.Data,
+		})
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", c.ID, vttTime(c.Start), vttTime(c.End), payload)
+	}
+	return b.String()
+}
+
+// vttTime formats seconds as a WebVTT timestamp (hh:mm:ss.ttt).
+func vttTime(sec float64) string {
+	ms := int64(sec*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// registerCues mounts the cue sheet endpoints.
+//
+//	POST /cues/start?session=id&offset=sec  begin a new session at video time offset
+//	GET  /cues/vtt?session=id               WebVTT metadata track (current session by default)
+//	GET  /cues/json?session=id              JSON cue sheet
+func registerCues(mux *http.ServeMux, cr *cueRecorder) {
+	mux.HandleFunc("/cues/start", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		offset := 0.0
+		if s := r.URL.Query().Get("offset"); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v < 0 {
+				http.Error(w, "invalid offset", http.StatusBadRequest)
+				return
+			}
+			offset = v
+		}
+		s, err := cr.start(r.URL.Query().Get("session"), offset)
+		if errors.Is(err, errCueSessionExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Info().Str("session", s.ID).Float64("offset", offset).Msg("cue session started")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"session": s.ID, "startedAt": s.StartedAt})
+	})
+
+	mux.HandleFunc("/cues/vtt", func(w http.ResponseWriter, r *http.Request) {
+		s, ok := cr.snapshot(r.URL.Query().Get("session"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		w.Write([]byte(s.webVTT()))
+	})
+
+	mux.HandleFunc("/cues/json", func(w http.ResponseWriter, r *http.Request) {
+		s, ok := cr.snapshot(r.URL.Query().Get("session"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s)
+	})
+}
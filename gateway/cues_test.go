@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVTTTime(t *testing.T) {
+	tests := []struct {
+		sec  float64
+		want string
+	}{
+		{0, "00:00:00.000"},
+		{1.5, "00:00:01.500"},
+		{59.9995, "00:01:00.000"},
+		{61.25, "00:01:01.250"},
+		{3723.004, "01:02:03.004"},
+		{36000, "10:00:00.000"},
+	}
+	for _, tt := range tests {
+		if got := vttTime(tt.sec); got != tt.want {
+			t.Errorf("vttTime(%v) = %q, want %q", tt.sec, got, tt.want)
+		}
+	}
+}
+
+func TestWebVTT(t *testing.T) {
+	s := &cueSession{
+		ID: "s1",
+		Cues: []*cue{
+			{ID: 1, Start: 0.5, Kind: "phase", Round: 3, Data: map[string]interface{}{"value": "betting"}},
+			{ID: 2, Start: 10, Kind: "result", Round: 3, Data: map[string]interface{}{"pocket": 17.0}},
+		},
+		ends: []float64{8, 0},
+	}
+	want := "WEBVTT - roulette game events\n\n" +
+		"1\n00:00:00.500 --> 00:00:08.000\n{\"data\":{\"value\":\"betting\"},\"kind\":\"phase\",\"round\":3}\n\n" +
+		"2\n00:00:10.000 --> 00:00:11.000\n{\"data\":{\"pocket\":17},\"kind\":\"result\",\"round\":3}\n\n"
+	if got := s.resolved().webVTT(); got != want {
+		t.Errorf("webVTT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCueSessionID(t *testing.T) {
+	dir := t.TempDir()
+	cr := newCueRecorder(filepath.Join(dir, "cues"))
+	for _, id := range []string{"../evil", "a/b", "..", "a b", strings.Repeat("x", 65)} {
+		if _, err := cr.start(id, 0); err == nil {
+			t.Errorf("start(%q) error = nil", id)
+		}
+	}
+
+	if _, err := cr.start("stream-1", 0); err != nil {
+		t.Fatal(err)
+	}
+	cr.observe([]byte(`{"type":"state","value":"betting","round":1}`))
+	if _, err := cr.start("stream_2", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cues", "stream-1.vtt")); err != nil {
+		t.Errorf("saved session: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("files outside the cue directory: %v", entries)
+	}
+
+	for i := 0; i < maxCueSessions+10; i++ {
+		cr.start(fmt.Sprintf("s%d", i), 0)
+	}
+	if len(cr.sessions) > maxCueSessions {
+		t.Errorf("%d sessions kept, want at most %d", len(cr.sessions), maxCueSessions)
+	}
+	if _, ok := cr.snapshot("stream-1"); ok {
+		t.Errorf("oldest session still kept")
+	}
+	if _, ok := cr.snapshot(""); !ok {
+		t.Errorf("current session dropped")
+	}
+}
+
+func TestCueSessionDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	cr := newCueRecorder(dir)
+	if _, err := cr.start("show", 0); err != nil {
+		t.Fatal(err)
+	}
+	cr.observe([]byte(`{"type":"state","value":"betting","round":1}`))
+	if _, err := cr.start("show", 0); !errors.Is(err, errCueSessionExists) {
+		t.Errorf("start(show) again error = %v, want %v", err, errCueSessionExists)
+	}
+	if s, _ := cr.snapshot(""); s.ID != "show" || len(s.Cues) != 1 {
+		t.Errorf("rejected start replaced the current session: %+v", s)
+	}
+
+	// generated IDs never collide, even within the same second
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		s, err := cr.start("", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[s.ID] {
+			t.Errorf("generated session id %s twice", s.ID)
+		}
+		seen[s.ID] = true
+	}
+
+	// a restarted gateway must not overwrite the saved files
+	saved, err := os.ReadFile(filepath.Join(dir, "show.vtt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	registerCues(mux, newCueRecorder(dir))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cues/start?session=show", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("POST /cues/start?session=show status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if now, _ := os.ReadFile(filepath.Join(dir, "show.vtt")); string(now) != string(saved) {
+		t.Errorf("saved cues changed to %q", now)
+	}
+}
@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+)
+
+// registerFair exposes the backend's provably fair service over HTTP.
+//
+//	GET /fair/commitment                                       current server seed hash
+//	GET /fair/reveal?hash=                                     a retired server seed
+//	GET /fair/verify?hash=&serverSeed=&clientSeed=&nonce=      recompute a round
+func registerFair(mux *http.ServeMux, cli proto.FairClient) {
+	call := func(w http.ResponseWriter, fn func(ctx context.Context) (interface{}, error)) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		resp, err := fn(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+
+	mux.HandleFunc("/fair/commitment", func(w http.ResponseWriter, r *http.Request) {
+		call(w, func(ctx context.Context) (interface{}, error) {
+			return cli.Commitment(ctx, &proto.RequestCommitment{})
+		})
+	})
+
+	mux.HandleFunc("/fair/reveal", func(w http.ResponseWriter, r *http.Request) {
+		call(w, func(ctx context.Context) (interface{}, error) {
+			return cli.RevealSeed(ctx, &proto.RequestRevealSeed{ServerSeedHash: r.URL.Query().Get("hash")})
+		})
+	})
+
+	mux.HandleFunc("/fair/verify", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		nonce, err := strconv.ParseUint(q.Get("nonce"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid nonce", http.StatusBadRequest)
+			return
+		}
+		call(w, func(ctx context.Context) (interface{}, error) {
+			return cli.Verify(ctx, &proto.RequestVerify{
+				ServerSeed:     q.Get("serverSeed"),
+				ServerSeedHash: q.Get("hash"),
+				ClientSeed:     q.Get("clientSeed"),
+				Nonce:          nonce,
+			})
+		})
+	})
+}
@@ -8,44 +8,76 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// client represents one websocket connection.
+// client represents one websocket connection or overlay subscriber.
 type client struct {
-	ws *websocket.Conn     // underlying socket
-	tx chan []byte         // outbound queue
-	id string              // just RemoteAddr for demo
+	ws      *websocket.Conn // underlying socket, nil for SSE subscribers
+	tx      chan []byte     // outbound queue
+	id      string          // just RemoteAddr for demo
+	player  string          // player id given when the socket was opened, empty if anonymous
+	overlay bool            // read-only overlay feed, never places bets
+}
+
+// close shuts the underlying socket, if any.
+func (c *client) close() {
+	if c.ws != nil {
+		_ = c.ws.Close()
+	}
 }
 
 // hub tracks all connected clients and broadcasts messages.
 type hub struct {
-	mu       sync.RWMutex
-	clients  map[*client]bool
-	register chan *client
+	mu         sync.RWMutex
+	clients    map[*client]bool
+	register   chan *client
+	unregister chan *client
+	observers  []func([]byte) // see every broadcast, e.g. the cue recorder
 }
 
 // newHub starts an event loop that keeps the maps in sync.
 func newHub() *hub {
 	h := &hub{
-		clients:  make(map[*client]bool),
-		register: make(chan *client, 16),
+		clients:    make(map[*client]bool),
+		register:   make(chan *client, 16),
+		unregister: make(chan *client, 16),
 	}
 	go h.run()
 	return h
 }
 
 func (h *hub) run() {
-	for c := range h.register {
-		h.mu.Lock()
-		h.clients[c] = true
-		h.mu.Unlock()
-		log.Debug().Str("id", c.id).Msg("new websocket client")
+	for {
+		select {
+		case c := <-h.register:
+			h.mu.Lock()
+			h.clients[c] = true
+			h.mu.Unlock()
+			log.Debug().Str("id", c.id).Bool("overlay", c.overlay).Msg("new websocket client")
+		case c := <-h.unregister:
+			h.mu.Lock()
+			if h.clients[c] {
+				delete(h.clients, c)
+				close(c.tx)
+			}
+			h.mu.Unlock()
+			log.Debug().Str("id", c.id).Bool("overlay", c.overlay).Msg("client left")
+		}
 	}
 }
 
-// broadcast encodes v as JSON and sends it to every client.
+// observe registers fn to receive the JSON of every later broadcast.
+func (h *hub) observe(fn func([]byte)) {
+	h.mu.Lock()
+	h.observers = append(h.observers, fn)
+	h.mu.Unlock()
+}
+
+// broadcast encodes v as JSON and sends it to every client. Observers are
+// called after the lock is released, so a slow one never blocks the hub.
 func (h *hub) broadcast(v interface{}) {
 	data, _ := json.Marshal(v)
 
-	h.mu.RLock()
+	h.mu.Lock()
+	observers := append([]func([]byte){}, h.observers...)
 	for c := range h.clients {
 		select {
 		case c.tx <- data:
@@ -53,8 +85,12 @@ func (h *hub) broadcast(v interface{}) {
 			// connection is stuck – drop it
 			close(c.tx)
 			delete(h.clients, c)
-			_ = c.ws.Close()
+			c.close()
 		}
 	}
-	h.mu.RUnlock()
-}
\ No newline at end of file
+	h.mu.Unlock()
+
+	for _, fn := range observers {
+		fn(data)
+	}
+}
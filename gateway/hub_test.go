@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// waitClients waits until the hub run loop has caught up to n clients.
+func waitClients(t *testing.T, h *hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		h.mu.RLock()
+		got := len(h.clients)
+		h.mu.RUnlock()
+		if got == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hub has %d clients, want %d", got, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHubUnregister(t *testing.T) {
+	h := newHub()
+	stay := &client{tx: make(chan []byte, 16), id: "stay"}
+	leave := &client{tx: make(chan []byte, 16), id: "leave"}
+	h.register <- stay
+	h.register <- leave
+	waitClients(t, h, 2)
+
+	h.broadcast(map[string]string{"type": "first"})
+	h.unregister <- leave
+	waitClients(t, h, 1)
+	h.broadcast(map[string]string{"type": "second"})
+
+	// the leaving client gets what was sent before, then a closed queue
+	if msg := <-leave.tx; !strings.Contains(string(msg), "first") {
+		t.Errorf("leaving client got %s, want first", msg)
+	}
+	if msg, ok := <-leave.tx; ok {
+		t.Errorf("leaving client got %s after unregister", msg)
+	}
+	for _, want := range []string{"first", "second"} {
+		if msg := <-stay.tx; !strings.Contains(string(msg), want) {
+			t.Errorf("remaining client got %s, want %s", msg, want)
+		}
+	}
+
+	// a second unregister of the same client must not close its queue again
+	h.unregister <- leave
+	h.register <- &client{tx: make(chan []byte, 16), id: "sync"}
+	waitClients(t, h, 2)
+}
+
+func TestOverlayDisconnect(t *testing.T) {
+	h := newHub()
+	mux := http.NewServeMux()
+	registerOverlay(mux, h, &roundMgr{}, &websocket.Upgrader{})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	dial := func() *websocket.Conn {
+		t.Helper()
+		ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/overlay/ws", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var hello map[string]interface{}
+		if err := ws.ReadJSON(&hello); err != nil || hello["type"] != "state" {
+			t.Fatalf("hello = %v, err = %v", hello, err)
+		}
+		return ws
+	}
+	stay := dial()
+	defer stay.Close()
+	leave := dial()
+	waitClients(t, h, 2)
+
+	leave.Close()
+	waitClients(t, h, 1)
+
+	// an SSE subscriber is dropped when its request ends
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/overlay/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "data: ") {
+		t.Fatalf("SSE hello = %q, err = %v", line, err)
+	}
+	waitClients(t, h, 2)
+	cancel()
+	resp.Body.Close()
+	waitClients(t, h, 1)
+
+	h.broadcast(map[string]string{"type": "result"})
+	var msg map[string]string
+	if err := stay.ReadJSON(&msg); err != nil || msg["type"] != "result" {
+		t.Errorf("remaining overlay got %v, err = %v", msg, err)
+	}
+}
+
+func TestObserverOutsideLock(t *testing.T) {
+	h := newHub()
+	c := &client{tx: make(chan []byte, 16), id: "c"}
+	h.register <- c
+	waitClients(t, h, 1)
+
+	// an observer that uses the hub itself must not deadlock the broadcast
+	got := make(chan string, 1)
+	h.observe(func(data []byte) {
+		h.mu.RLock()
+		n := len(h.clients)
+		h.mu.RUnlock()
+		if n == 1 {
+			got <- string(data)
+		}
+	})
+	done := make(chan struct{})
+	go func() {
+		h.broadcast(map[string]string{"type": "result"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcast blocked on its observer")
+	}
+	if msg := <-got; !strings.Contains(msg, "result") {
+		t.Errorf("observer got %s", msg)
+	}
+	if msg := <-c.tx; !strings.Contains(string(msg), "result") {
+		t.Errorf("client got %s", msg)
+	}
+}
@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// sseKeepAlive is how often an idle SSE stream gets a comment line so
+// proxies and OBS browser sources do not time it out.
+const sseKeepAlive = 15 * time.Second
+
+// registerOverlay mounts the read-only overlay feed. Overlay pages receive
+// the same hub broadcasts as players (phases, bets, results, cards) but
+// anything they send is ignored.
+//
+//	/overlay/ws      websocket, one JSON message per event
+//	/overlay/events  Server-Sent Events, one "data:" line per event
+func registerOverlay(mux *http.ServeMux, h *hub, rm *roundMgr, upgrader *websocket.Upgrader) {
+	mux.HandleFunc("/overlay/ws", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := &client{
+			ws:      ws,
+			tx:      make(chan []byte, 16),
+			id:      r.RemoteAddr,
+			overlay: true,
+		}
+		hello, _ := json.Marshal(rm.snapshotState())
+		c.tx <- hello
+		h.register <- c
+
+		// writer
+		go func() {
+			for msg := range c.tx {
+				if err := c.ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+					log.Debug().Err(err).Str("id", c.id).Msg("overlay write failed")
+				}
+			}
+		}()
+
+		// reader: only there to notice the socket closing
+		go func() {
+			defer func() {
+				h.unregister <- c
+				c.close()
+			}()
+			for {
+				if _, _, err := c.ws.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+	})
+
+	mux.HandleFunc("/overlay/events", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		c := &client{
+			tx:      make(chan []byte, 16),
+			id:      r.RemoteAddr,
+			overlay: true,
+		}
+		hello, _ := json.Marshal(rm.snapshotState())
+		c.tx <- hello
+		h.register <- c
+		defer func() { h.unregister <- c }()
+
+		ping := time.NewTicker(sseKeepAlive)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg, ok := <-c.tx:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(w, "data: %s\n\n", msg)
+				flusher.Flush()
+			case <-ping.C:
+				fmt.Fprint(w, ": ping\n\n")
+				flusher.Flush()
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/store"
+)
+
+// storeTable is the table name gateway rounds are stored under.
+const storeTable = "gateway"
+
+// beginRound records the closed bets before the spin is requested.
+func (rm *roundMgr) beginRound(req *proto.RequestPlay, live []liveBet) error {
+	if rm.store == nil {
+		return nil
+	}
+	reqJSON, err := protojson.Marshal(req)
+	if err != nil {
+		return err
+	}
+	bets, err := json.Marshal(live)
+	if err != nil {
+		return err
+	}
+	return rm.store.Begin(&store.Round{
+		Table:   storeTable,
+		Round:   uint64(rm.round),
+		RoundID: req.RoundId,
+		Request: reqJSON,
+		Bets:    bets,
+	})
+}
+
+// outcomeRound records the backend result before it is broadcast.
+func (rm *roundMgr) outcomeRound(resp *proto.ReplyPlay) {
+	if rm.store == nil {
+		return
+	}
+	b, err := protojson.Marshal(resp)
+	if err == nil {
+		err = rm.store.Outcome(storeTable, uint64(rm.round), b)
+	}
+	if err != nil {
+		log.Err(err).Int64("round", rm.round).Msg("failed to store round outcome")
+	}
+}
+
+// settleRound marks the round settled once the result is broadcast.
+func (rm *roundMgr) settleRound() {
+	if rm.store == nil {
+		return
+	}
+	if err := rm.store.Settle(storeTable, uint64(rm.round), nil); err != nil {
+		log.Err(err).Int64("round", rm.round).Msg("failed to store round settlement")
+	}
+}
+
+// voidRound marks the round void; its bets are returned.
+func (rm *roundMgr) voidRound(reason string) {
+	if rm.store == nil {
+		return
+	}
+	if err := rm.store.Void(storeTable, uint64(rm.round), reason); err != nil {
+		log.Err(err).Int64("round", rm.round).Msg("failed to store void round")
+	}
+}
+
+// recoverRounds finishes the rounds a crash left open and continues the
+// round counter. A round with a recorded outcome is settled with it. For a
+// round that only has bets, the backend is asked for the result it kept
+// under the round ID; the round is never spun again. If the backend has no
+// result, or cannot be asked, the round is voided.
+func (rm *roundMgr) recoverRounds() {
+	if rm.store == nil {
+		return
+	}
+	for _, r := range rm.store.Unfinished(storeTable) {
+		l := log.Warn().Uint64("round", r.Round).Str("roundId", r.RoundID)
+		if r.State == store.StateOutcome {
+			l.Msg("settling unfinished round with its recorded outcome")
+			if err := rm.store.Settle(storeTable, r.Round, nil); err != nil {
+				log.Err(err).Uint64("round", r.Round).Msg("failed to store round settlement")
+			}
+			continue
+		}
+
+		resp, err := rm.result(r.RoundID)
+		if err == nil {
+			var b []byte
+			if b, err = protojson.Marshal(resp); err == nil {
+				err = rm.store.Settle(storeTable, r.Round, b)
+			}
+			if err != nil {
+				log.Err(err).Uint64("round", r.Round).Msg("failed to store round settlement")
+				continue
+			}
+			l.Msg("settled unfinished round with the backend result")
+			continue
+		}
+		l.Err(err).Msg("voiding unfinished round")
+		if err := rm.store.Void(storeTable, r.Round, "gateway restarted: "+err.Error()); err != nil {
+			log.Err(err).Uint64("round", r.Round).Msg("failed to store void round")
+		}
+	}
+	rm.round = int64(rm.store.Last(storeTable)) + 1
+}
+
+// result looks up the backend result of a round by its round ID.
+func (rm *roundMgr) result(roundID string) (*proto.ReplyPlay, error) {
+	if roundID == "" {
+		return nil, fmt.Errorf("round has no round ID")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return rm.grpc.GetResult(ctx, &proto.RequestResult{RoundId: roundID})
+}
@@ -3,27 +3,40 @@ package gateway
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"gitee.com/heartfun/rouletteserv/proto"
 	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/store"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // phases of one roulette round.
 type phase string
 
 const (
-	phaseOpen   phase = "open"   // accepting bets
-	phaseResult phase = "result" // publish winning number
-	phasePause  phase = "pause"  // bets closed, waiting
+	phaseOpen      phase = "open"      // accepting bets
+	phaseResult    phase = "result"    // publish winning number
+	phasePause     phase = "pause"     // bets closed, waiting
+	phaseSuspended phase = "suspended" // no RNG available, bets held until the table resumes
 )
 
-// liveBet mirrors proto.Bet plus a player id.
+// suspendRetry is how often a suspended table retries the spin.
+var suspendRetry = 5 * time.Second
+
+// playAttempts is how many times a timed out Play2 is sent.
+const playAttempts = 3
+
+// liveBet mirrors proto.Bet plus the connection and player it came from.
 type liveBet struct {
-	Client string      `json:"client"`
-	Bet    *proto.Bet  `json:"bet"`
+	Client string     `json:"client"`
+	Player string     `json:"player,omitempty"`
+	Bet    *proto.Bet `json:"bet"`
 }
 
 // roundMgr drives the game loop and talks to the gRPC backend.
@@ -33,13 +46,30 @@ type roundMgr struct {
 	h    *hub
 	grpc proto.GameLogicClient
 
-	round int64
+	clips       *game.ClipLibrary // optional, picks the video for each result
+	clipVariant string            // camera/wheel variant of this table, empty for the manifest default
+	rng         game.RNGClient    // optional, used for clip selection
+
+	round   int64
+	session string // prefixes round IDs so a restarted gateway never reuses one
 
 	mu   sync.Mutex
 	bets []liveBet
 	curPhase  phase
 
 	manually bool
+	spinMu   sync.Mutex // held while a spin runs, so only one resultPhase runs at a time
+
+	cheat string // development only, sent with the next Play2 call
+
+	store store.Store // optional, persists every round
+}
+
+// setCheat queues a cheat for the next spin, replacing one not yet sent.
+func (rm *roundMgr) setCheat(c string) {
+	rm.mu.Lock()
+	rm.cheat = c
+	rm.mu.Unlock()
 }
 
 func (rm *roundMgr) setPhase(p phase) {
@@ -48,15 +78,21 @@ func (rm *roundMgr) setPhase(p phase) {
     rm.mu.Unlock()
 }
 
-func newRoundMgr(h *hub, cli proto.GameLogicClient, betWin, pauseWin time.Duration) *roundMgr {
+func newRoundMgr(h *hub, cli proto.GameLogicClient, clips *game.ClipLibrary, clipVariant string, rngClient game.RNGClient, st store.Store, betWin, pauseWin time.Duration) *roundMgr {
 	rm := &roundMgr{
-		h:        h,
-		grpc:     cli,
-		betWin:   betWin,
-		pauseWin: pauseWin,
-		round:    1,
-		manually:  betWin == 0 && pauseWin == 0,
+		h:           h,
+		grpc:        cli,
+		clips:       clips,
+		clipVariant: clipVariant,
+		rng:         rngClient,
+		betWin:      betWin,
+		pauseWin:    pauseWin,
+		round:       1,
+		session:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		manually:    betWin == 0 && pauseWin == 0,
+		store:       st,
 	}
+	rm.recoverRounds()
 
     if !rm.manually {
         go rm.loop()
@@ -71,8 +107,22 @@ func (rm *roundMgr) loop() {
 	for {
 		rm.openPhase()
 		rm.pausePhase()
-		rm.resultPhase()
+		rm.spin()
+	}
+}
+
+// spin runs resultPhase until the round is settled. A paused table keeps the
+// round and its bets and retries every suspendRetry. It returns false without
+// spinning while another spin is still running.
+func (rm *roundMgr) spin() bool {
+	if !rm.spinMu.TryLock() {
+		return false
+	}
+	defer rm.spinMu.Unlock()
+	for !rm.resultPhase() {
+		time.Sleep(suspendRetry)
 	}
+	return true
 }
 
 func (rm *roundMgr) openPhase() {
@@ -101,15 +151,35 @@ func (rm *roundMgr) pausePhase() {
 	})
 }
 
-func (rm *roundMgr) resultPhase() {
+// play calls Play2, retrying timed out calls with the same round ID so the
+// backend returns the stored result instead of spinning again.
+func (rm *roundMgr) play(req *proto.RequestPlay) (*proto.ReplyPlay, error) {
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		resp, err := rm.grpc.Play2(ctx, req)
+		cancel()
+		if status.Code(err) != codes.DeadlineExceeded || attempt == playAttempts {
+			return resp, err
+		}
+		log.Warn().Err(err).Str("roundId", req.RoundId).Int("attempt", attempt).Msg("Play2 timed out, retrying")
+	}
+}
+
+// resultPhase spins and publishes the result. It returns false when the
+// backend paused the table because no RNG is available; the round is then
+// neither settled nor advanced. Callers go through spin.
+func (rm *roundMgr) resultPhase() bool {
     rm.setPhase(phaseResult)
 
 	// 1) take a snapshot of all live bets
 	live := rm.snapshotBets()
 
-	// 2) convert liveBet → *proto.Bet (server does not need client id)
+	// 2) convert liveBet → *proto.Bet
 	bets := make([]*proto.Bet, 0, len(live))
 	for _, lb := range live {
+		// tag every bet with its player for the round history; bets of an
+		// anonymous connection carry none, whatever the payload said
+		lb.Bet.Player = lb.Player
 		bets = append(bets, lb.Bet)
 	}
 
@@ -120,17 +190,51 @@ func (rm *roundMgr) resultPhase() {
 
 
 	// 4) build the gRPC request
+	rm.mu.Lock()
+	pending := rm.cheat
+	rm.mu.Unlock()
 	req := &proto.RequestPlay{
         ClientParams: string(j),
+        Cheat:        pending,
+        RoundId:      fmt.Sprintf("%s-%d", rm.session, rm.round),
     }
 
-	// 5) call Play2
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	resp, err := rm.grpc.Play2(ctx, req)
+	// 5) persist the bets, then call Play2
+	if err := rm.beginRound(req, live); err != nil {
+		// never spin bets that could be lost in a crash
+		log.Err(err).Int64("round", rm.round).Msg("failed to store round, table suspended")
+		rm.setPhase(phaseSuspended)
+		rm.h.broadcast(map[string]interface{}{
+			"type":  "state",
+			"value": phaseSuspended,
+			"round": rm.round,
+			"error": "round store unavailable",
+		})
+		return false
+	}
+	resp, err := rm.play(req)
+	if pending != "" && status.Code(err) != codes.Unavailable {
+		// the backend took the cheat; a suspended table sends it again
+		rm.mu.Lock()
+		if rm.cheat == pending {
+			rm.cheat = ""
+		}
+		rm.mu.Unlock()
+	}
+	if status.Code(err) == codes.Unavailable {
+		log.Warn().Err(err).Int64("round", rm.round).Msg("table suspended")
+		rm.setPhase(phaseSuspended)
+		rm.h.broadcast(map[string]interface{}{
+			"type":  "state",
+			"value": phaseSuspended,
+			"round": rm.round,
+			"error": err.Error(),
+		})
+		return false
+	}
 	if err != nil {
 		log.Err(err).Msg("Play2 failed")
+		rm.voidRound(err.Error())
 		rm.h.broadcast(map[string]interface{}{
 			"type":  "state",
 			"value": phaseResult,
@@ -138,23 +242,63 @@ func (rm *roundMgr) resultPhase() {
 			"error": err.Error(),
 		})
 		rm.round++
-		return
+		return true
 	}
 
+	rm.outcomeRound(resp)
+
     // --- decode the wheel pocket -----------------------------------------
     var pocket int32
     if len(resp.RandomNumbers) != 0 {
         pocket = resp.RandomNumbers[0].Value % int32(game.NumberCount) // 0-36
     }
 
-	// 6) broadcast the result
-	rm.h.broadcast(map[string]interface{}{
+    // --- the spin every overlay must animate identically ------------------
+    var wheel *proto.WheelAnimation
+    var proof *proto.FairProof
+    var cheated string
+    if len(resp.Results) != 0 && resp.Results[0].ClientData != nil {
+        var gmp proto.GameModParam
+        if err := resp.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err == nil {
+            pocket = gmp.WinningNumber
+            wheel = gmp.Wheel
+            proof = gmp.Fair
+            cheated = gmp.Cheat
+        }
+    }
+
+	msg := map[string]interface{}{
 		"type":  "state",
 		"value": phaseResult,
 		"round": rm.round,
 		"data":  resp,
 		"pocket": pocket,
-	})
+		"wheel": wheel,
+	}
+	if proof != nil {
+		msg["fair"] = proof
+	}
+	if cheated != "" {
+		log.Warn().Int64("round", rm.round).Int32("pocket", pocket).Str("cheat", cheated).Msg("cheated round")
+		msg["cheated"] = true
+		msg["cheat"] = cheated
+	}
+
+	// 6) pick the pre-recorded clip only now that the outcome is fixed
+	if rm.clips != nil {
+		clip, err := rm.clips.Pick(int(pocket), rm.clipVariant, rm.rng)
+		if err != nil {
+			log.Err(err).Int32("pocket", pocket).Msg("no clip for result")
+		} else {
+			msg["clip"] = clip.ID
+			msg["video"] = clip
+			msg["overlayAt"] = clip.ResultAt
+		}
+	}
+
+	// 7) broadcast the result
+	rm.h.broadcast(msg)
+	rm.settleRound()
 
 	rm.round++
 
@@ -163,6 +307,7 @@ func (rm *roundMgr) resultPhase() {
     } else {
         rm.openPhase()
     }
+    return true
 }
 
 func (rm *roundMgr) addBet(cl *client, b *proto.Bet) {
@@ -183,7 +328,7 @@ func (rm *roundMgr) addBet(cl *client, b *proto.Bet) {
 
 	// 1) remember it for the current round
 	rm.mu.Lock()
-	rm.bets = append(rm.bets, liveBet{Client: cl.id, Bet: b})
+	rm.bets = append(rm.bets, liveBet{Client: cl.id, Player: cl.player, Bet: b})
 	rm.mu.Unlock()
 
 	// 2) log to console
@@ -201,6 +346,17 @@ func (rm *roundMgr) addBet(cl *client, b *proto.Bet) {
     })
 }
 
+// snapshotState describes the current phase for clients joining mid-round.
+func (rm *roundMgr) snapshotState() map[string]interface{} {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return map[string]interface{}{
+		"type":  "state",
+		"value": rm.curPhase,
+		"round": rm.round,
+	}
+}
+
 func (rm *roundMgr) resetBets() {
 	rm.mu.Lock()
 	rm.bets = nil
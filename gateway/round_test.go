@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyBackend answers Play2 with Unavailable a number of times, then with
+// an empty result, and records how many calls overlapped.
+type flakyBackend struct {
+	proto.GameLogicClient
+
+	mu          sync.Mutex
+	unavailable int
+	calls       int
+	inFlight    int
+	maxInFlight int
+	first       chan struct{} // closed when the first call arrives
+	release     chan struct{} // the first call waits for it
+}
+
+func (b *flakyBackend) Play2(ctx context.Context, in *proto.RequestPlay, opts ...grpc.CallOption) (*proto.ReplyPlay, error) {
+	b.mu.Lock()
+	b.calls++
+	call := b.calls
+	b.inFlight++
+	b.maxInFlight = max(b.maxInFlight, b.inFlight)
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.inFlight--
+		b.mu.Unlock()
+	}()
+
+	if call == 1 {
+		close(b.first)
+		<-b.release
+	}
+	if call <= b.unavailable {
+		return nil, status.Error(codes.Unavailable, "no RNG")
+	}
+	return &proto.ReplyPlay{}, nil
+}
+
+func TestManualSpinRetry(t *testing.T) {
+	defer func(d time.Duration) { suspendRetry = d }(suspendRetry)
+	suspendRetry = 10 * time.Millisecond
+
+	backend := &flakyBackend{unavailable: 2, first: make(chan struct{}), release: make(chan struct{})}
+	rm := &roundMgr{h: newHub(), grpc: backend, round: 1, session: "test", manually: true}
+	rm.openPhase()
+
+	done := make(chan bool)
+	go func() { done <- rm.spin() }()
+	<-backend.first
+
+	// a second spin while the first one is running is ignored
+	if rm.spin() {
+		t.Error("spin() during a running spin = true")
+	}
+	close(backend.release)
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("spin() = false")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("suspended manual round was not retried")
+	}
+
+	backend.mu.Lock()
+	calls, maxInFlight := backend.calls, backend.maxInFlight
+	backend.mu.Unlock()
+	if calls != 3 || maxInFlight != 1 {
+		t.Errorf("Play2 calls = %d, max in flight = %d, want 3, 1", calls, maxInFlight)
+	}
+	if rm.round != 2 || rm.curPhase != phaseOpen {
+		t.Errorf("after spin round = %d, phase = %s, want 2, %s", rm.round, rm.curPhase, phaseOpen)
+	}
+}
+
+// recordingBackend keeps the last Play2 request.
+type recordingBackend struct {
+	proto.GameLogicClient
+	req *proto.RequestPlay
+}
+
+func (b *recordingBackend) Play2(ctx context.Context, in *proto.RequestPlay, opts ...grpc.CallOption) (*proto.ReplyPlay, error) {
+	b.req = in
+	return &proto.ReplyPlay{}, nil
+}
+
+func TestBetPlayer(t *testing.T) {
+	backend := &recordingBackend{}
+	rm := &roundMgr{h: newHub(), grpc: backend, round: 1, session: "test", manually: true}
+	rm.openPhase()
+
+	known := &client{tx: make(chan []byte, 16), id: "10.0.0.1:5000", player: "alice"}
+	anon := &client{tx: make(chan []byte, 16), id: "10.0.0.2:5000"}
+	rm.addBet(known, &proto.Bet{Numbers: []int32{17}, Amount: 10})
+	rm.addBet(anon, &proto.Bet{Numbers: []int32{5}, Amount: 10, Player: "alice"})
+	rm.spin()
+
+	var params struct {
+		Bets []*proto.Bet `json:"bets"`
+	}
+	if err := json.Unmarshal([]byte(backend.req.ClientParams), &params); err != nil {
+		t.Fatal(err)
+	}
+	if len(params.Bets) != 2 || params.Bets[0].Player != "alice" || params.Bets[1].Player != "" {
+		t.Errorf("bets sent = %+v, want players alice and none", params.Bets)
+	}
+}
+
+func TestValidPlayer(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"", true},
+		{"alice", true},
+		{"user-42@site.example", true},
+		{"a:b_c.d", true},
+		{"a/b", false},
+		{"has space", false},
+		{"<script>", false},
+		{strings.Repeat("a", maxPlayerID), true},
+		{strings.Repeat("a", maxPlayerID+1), false},
+	}
+	for _, tt := range tests {
+		if got := validPlayer(tt.id); got != tt.want {
+			t.Errorf("validPlayer(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
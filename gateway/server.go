@@ -4,42 +4,111 @@ import (
 	"net/http"
 	"time"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
+	"gitee.com/heartfun/rouletteserv/cheat"
+	"gitee.com/heartfun/rouletteserv/game"
 	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/rng"
+	"gitee.com/heartfun/rouletteserv/store"
 )
 
 // Start boots the websocket gateway and never returns unless an error occurs.
-func Start(addr, rouletteAddr string, betWin, pauseWin time.Duration) error {
+// rngAddr is optional; without it overlay cards are drawn locally.
+// cueDir is optional; when set every finished cue session is saved there.
+// clipsPath is optional; it names a clip manifest used to announce the
+// pre-recorded video for every result. clipVariant selects this table's
+// variant from the manifest, empty for the manifest default.
+// storePath is optional; when set every round is persisted there and rounds
+// left unfinished by a crash are settled or voided on startup.
+// cheats enables the development-only /api/cheat endpoint.
+func Start(addr, rouletteAddr, rngAddr, cueDir, clipsPath, clipVariant, storePath string, betWin, pauseWin time.Duration, cheats bool) error {
 	grpcConn, err := grpc.Dial(rouletteAddr, grpc.WithInsecure())
 	if err != nil {
 		return err
 	}
 	defer grpcConn.Close()
 
+	// cards and clip selection draw from their own RNG streams
+	var cardRng, clipRng game.RNGClient
+	if rngAddr != "" {
+		client, err := rng.NewRNGClient(rngAddr)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		cardRng = client.Stream("cards", "")
+		clipRng = client.Stream("clips", "")
+	}
+
+	var clips *game.ClipLibrary
+	if clipsPath != "" {
+		if clips, err = game.LoadClipLibrary(clipsPath); err != nil {
+			return err
+		}
+		if clipVariant != "" && !clips.HasVariant(clipVariant) {
+			return fmt.Errorf("clip manifest has no variant %q", clipVariant)
+		}
+	}
+
+	var st store.Store
+	if storePath != "" {
+		fs, err := store.OpenFile(storePath)
+		if err != nil {
+			return err
+		}
+		defer fs.Close()
+		st = fs
+	}
+
 	h := newHub()
+	cues := newCueRecorder(cueDir)
+	h.observe(cues.observe)
 	rm := newRoundMgr(
-    		h,
-    		proto.NewGameLogicClient(grpcConn),
-    		betWin,
-    		pauseWin,
-    )
+		h,
+		proto.NewGameLogicClient(grpcConn),
+		clips,
+		clipVariant,
+		clipRng,
+		st,
+		betWin,
+		pauseWin,
+	)
 
+	dealer := &cardDealer{rng: cardRng, h: h}
+	if cheats {
+		log.Warn().Msg("CHEATS ENABLED - spins and cards can be forced, development use only")
+		dealer.cheats = &cheat.Queue{}
+		registerCheat(http.DefaultServeMux, rm, dealer)
+	}
 
 	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
 
+	registerOverlay(http.DefaultServeMux, h, rm, &upgrader)
+	http.Handle("/api/random_card", dealer)
+	registerCues(http.DefaultServeMux, cues)
+	registerFair(http.DefaultServeMux, proto.NewFairClient(grpcConn))
+
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		player := r.URL.Query().Get("player")
+		if !validPlayer(player) {
+			http.Error(w, "invalid player id", http.StatusBadRequest)
+			return
+		}
 		ws, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			return
 		}
 		c := &client{
-			ws: ws,
-			tx: make(chan []byte, 16),
-			id: r.RemoteAddr,
+			ws:     ws,
+			tx:     make(chan []byte, 16),
+			id:     r.RemoteAddr,
+			player: player,
 		}
 		h.register <- c
 
@@ -68,7 +137,11 @@ func Start(addr, rouletteAddr string, betWin, pauseWin time.Duration) error {
                 }
                 if err := json.Unmarshal(msg, &spinProbe); err == nil && spinProbe.Spin != nil {
                 	if rm.manually {
-                		go rm.resultPhase()
+                		go func() {
+                			if !rm.spin() {
+                				log.Warn().Str("client", c.id).Msg("spin ignored, the previous spin is still running")
+                			}
+                		}()
                 	}
                 	continue // nothing else to do with this message
                 }
@@ -93,4 +166,25 @@ func Start(addr, rouletteAddr string, betWin, pauseWin time.Duration) error {
 
 	log.Info().Str("addr", addr).Msg("gateway listening")
 	return http.ListenAndServe(":"+addr, nil)
+}
+
+// maxPlayerID is the longest player id a socket may declare.
+const maxPlayerID = 64
+
+// validPlayer accepts an empty (anonymous) id or up to maxPlayerID letters,
+// digits and ._:@- characters. The id is taken as given; authenticating it is
+// up to the site in front of the gateway.
+func validPlayer(id string) bool {
+	if len(id) > maxPlayerID {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("._:@-", r):
+		default:
+			return false
+		}
+	}
+	return true
 }
\ No newline at end of file
@@ -3,13 +3,14 @@ module gitee.com/heartfun/rouletteserv
 go 1.24.1
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	github.com/rs/zerolog v1.34.0
+	golang.org/x/crypto v0.32.0
 	google.golang.org/grpc v1.71.0
 	google.golang.org/protobuf v1.36.6
 )
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
 	golang.org/x/net v0.34.0 // indirect
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"gitee.com/heartfun/rouletteserv/bridge"
+	"gitee.com/heartfun/rouletteserv/fair"
 	"gitee.com/heartfun/rouletteserv/game"
 	"gitee.com/heartfun/rouletteserv/rng"
+	"gitee.com/heartfun/rouletteserv/rngtest"
 	"gitee.com/heartfun/rouletteserv/server"
 	"gitee.com/heartfun/rouletteserv/gateway"
 	"github.com/rs/zerolog"
@@ -16,14 +22,55 @@ import (
 
 func main() {
 	// 解析命令行参数
-	mode := flag.String("mode", "roulette", "Service mode: roulette or rng")
+	mode := flag.String("mode", "roulette", "Service mode: roulette, rng, rtp, gateway, bridge, verify, replay, recheck, session, rngtest or audit")
 	port := flag.String("port", "6000", "Port to listen on")
-	rngAddr := flag.String("rng", "", "Address of RNG service (optional for roulette mode)")
+	rngAddr := flag.String("rng", "", "Address of RNG service, comma separated in priority order (optional for roulette mode)")
+	rngPolicy := flag.String("rngPolicy", rng.PolicyFailClosed, "With several RNG services: failclosed pauses the table, failover switches to the next healthy one")
 	numRounds := flag.String("count", "100000000", "Number of rounds to calculate RTP for (optional for rtp mode)")
 	debug := flag.Bool("debug", false, "sets log level to debug")
 	betWindow  := flag.Int("betWindow", 30, "bet window length in seconds")
     pauseWindow := flag.Int("pauseWindow", 10, "pause window length in seconds")
     rouletteAddr := flag.String("roulette", "localhost:6000", "Address of Roulette service")
+	cueDir := flag.String("cueDir", "", "Directory for WebVTT/JSON cue sheets (optional for gateway mode)")
+	clips := flag.String("clips", "", "Clip library manifest mapping pockets to videos (optional for gateway mode)")
+	clipVariant := flag.String("clipVariant", "", "Clip variant (camera or wheel) of this table, empty for the manifest default (gateway mode)")
+	listen := flag.String("listen", "", "Listen address host:port (bridge mode, overrides -port)")
+	cors := flag.String("cors", "", "Comma separated CORS origin allowlist, * for any (bridge mode)")
+	apiKey := flag.String("apiKey", "", "API key required by bridge endpoints (optional)")
+	fairMode := flag.Bool("fair", false, "Provably fair mode for roulette spins")
+	fairSeeds := flag.String("fairSeeds", "", "File persisting provably fair server seeds (optional)")
+	fairRotate := flag.Uint64("fairRotate", 0, "Rotate the provably fair server seed every N rounds, 0 for manual")
+	serverSeed := flag.String("serverSeed", "", "Revealed server seed (verify mode)")
+	clientSeed := flag.String("clientSeed", "", "Client seed (verify mode)")
+	nonce := flag.Uint64("nonce", 0, "Round nonce (verify mode)")
+	replyPath := flag.String("reply", "", "ReplyPlay JSON file to re-verify (verify mode)")
+	seedHex := flag.String("seed", "", "Hex master seed for the deterministic QA RNG (roulette mode, never in production)")
+	table := flag.String("table", "default", "Table ID used to derive per-round seeds")
+	roundLogPath := flag.String("roundLog", "", "Append every round to this JSONL file (roulette mode), or the file to replay (replay and recheck mode)")
+	auditPath := flag.String("audit", "", "RNG audit log recording every draw per round (roulette mode), or the log to query (audit mode)")
+	auditRound := flag.Uint64("round", 0, "Round to look up in the RNG audit log of -table (audit mode), or to recheck, 0 for all (recheck mode)")
+	production := flag.Bool("production", false, "Production configuration, refuses QA-only features")
+	roundRetention := flag.Duration("roundRetention", server.DefaultRoundRetention, "Keep completed results this long so retried plays with the same round ID return them (roulette mode)")
+	storePath := flag.String("store", "", "Round store persisting bets, outcomes and settlements; unfinished rounds are recovered on startup (roulette and gateway mode), or the store to recheck (recheck mode)")
+	adminPort := flag.String("adminPort", "", "Admin port serving RoundHistory and FairAdmin (roulette mode) or RngAdmin (rng mode); keep it off the public network")
+	historyPath := flag.String("history", "", "Round history file, served by RoundHistory on -adminPort (roulette mode), or the history to recheck (recheck mode)")
+	stateKeyHex := flag.String("stateKey", "", "Hex key signing the private player state, shared by servers that continue each other's sessions; random per start when empty (roulette and replay mode)")
+	maxStake := flag.Int64("maxStake", 0, "Maximum total stake of one round, also applied to rebet and double, 0 for no limit (roulette mode)")
+	enPrison := flag.Bool("enPrison", false, "En Prison rule: even-money bets losing to zero are held in the player state and decided by the next spin (roulette mode)")
+	cheats := flag.Bool("cheats", false, "Enable cheat commands forcing spins and cards (roulette and gateway mode, development only)")
+	capturePath := flag.String("capture", "requests.jsonl", "Recorded requests, and optionally replies, to send to -roulette (session mode)")
+	pin := flag.Bool("pin", false, "Force each recorded winning number with a cheat, the server needs -cheats (session mode)")
+	samples := flag.Int("samples", 1000000, "Values per sample (rngtest mode)")
+	ranges := flag.String("ranges", "37,52", "Comma separated ranges to test scaled output for (rngtest mode)")
+	alpha := flag.Float64("alpha", 0.01, "Two-sided significance level of the whole suite, split over the tests by Šidák correction (rngtest mode)")
+	reportPath := flag.String("report", "", "Report path prefix, writes .json and .txt (rngtest mode)")
+	rngAlg := flag.String("rngAlg", rng.AlgCrypto, "RNG generator: "+strings.Join(rng.Algorithms, ", ")+" (rng and rngtest mode)")
+	rngReseed := flag.Uint64("rngReseed", rng.DefaultReseedInterval, "Reseed the DRBG from the OS every N generate requests (rng and rngtest mode)")
+	rngPoolLow := flag.Int("rngPoolLow", rng.DefaultPoolLow, "Refill the local RNG pool below this many values (roulette mode with -rng)")
+	rngPoolHigh := flag.Int("rngPoolHigh", rng.DefaultPoolHigh, "Refill the local RNG pool up to this many values, 0 disables prefetching (roulette mode with -rng)")
+	rngTimeout := flag.Duration("rngTimeout", rng.DefaultCallTimeout, "Deadline of every RNG service call (roulette mode with -rng)")
+	rngPoolReport := flag.Duration("rngPoolReport", server.DefaultPoolReport, "Log the local RNG pool stats this often, 0 disables the report (roulette mode with -rng)")
+	rngReseedPeriod := flag.Duration("rngReseedPeriod", rng.DefaultReseedPeriod, "Reseed the DRBG from the OS at least this often (rng and rngtest mode)")
 	flag.Parse()
 
 	if modeStr := os.Getenv("MODE"); modeStr != "" {
@@ -32,9 +79,30 @@ func main() {
 	if portStr := os.Getenv("PORT"); portStr != "" {
 		*port = portStr
 	}
+	if adminPortStr := os.Getenv("ADMIN_PORT"); adminPortStr != "" {
+		*adminPort = adminPortStr
+	}
 	if rngAddrStr := os.Getenv("RNG"); rngAddrStr != "" {
 		*rngAddr = rngAddrStr
 	}
+	if rngAlgStr := os.Getenv("RNG_ALG"); rngAlgStr != "" {
+		*rngAlg = rngAlgStr
+	}
+	if rngPolicyStr := os.Getenv("RNG_POLICY"); rngPolicyStr != "" {
+		*rngPolicy = rngPolicyStr
+	}
+	if apiKeyStr := os.Getenv("API_KEY"); apiKeyStr != "" {
+		*apiKey = apiKeyStr
+	}
+	if productionStr := os.Getenv("PRODUCTION"); productionStr != "" {
+		*production = productionStr == "true"
+	}
+	if stateKeyStr := os.Getenv("STATE_KEY"); stateKeyStr != "" {
+		*stateKeyHex = stateKeyStr
+	}
+	if cheatsStr := os.Getenv("CHEATS"); cheatsStr != "" {
+		*cheats = cheatsStr == "true"
+	}
 	if debugStr := os.Getenv("DEBUG"); debugStr != "" {
 		*debug = debugStr == "true"
 	}
@@ -45,28 +113,214 @@ func main() {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	}
 
+	var stateKey []byte
+	if *stateKeyHex != "" {
+		b, err := hex.DecodeString(*stateKeyHex)
+		if err != nil || len(b) < 16 {
+			log.Error().Msg("Invalid state key, must be at least 16 bytes of hex")
+			os.Exit(1)
+		}
+		stateKey = b
+	}
+
 	switch *mode {
 	case "roulette":
-		if err := server.StartServer(*port, *rngAddr); err != nil {
+		var fairMgr *fair.Manager
+		if *fairMode {
+			m, err := fair.NewManager(*fairSeeds, *fairRotate)
+			if err != nil {
+				log.Err(err).Msg("Failed to load provably fair seeds")
+				os.Exit(1)
+			}
+			fairMgr = m
+		}
+		var seed []byte
+		if *seedHex != "" {
+			b, err := hex.DecodeString(*seedHex)
+			if err != nil || len(b) == 0 {
+				log.Error().Msg("Invalid seed, must be hex")
+				os.Exit(1)
+			}
+			seed = b
+		}
+		if err := server.StartServer(server.Config{
+			Port:           *port,
+			RngAddr:        *rngAddr,
+			Fair:           fairMgr,
+			Seed:           seed,
+			Table:          *table,
+			RoundLog:       *roundLogPath,
+			Production:     *production,
+			RngPolicy:      *rngPolicy,
+			AuditLog:       *auditPath,
+			Cheats:         *cheats,
+			RoundRetention: *roundRetention,
+			Store:          *storePath,
+			History:        *historyPath,
+			AdminPort:      *adminPort,
+			EnPrison:       *enPrison,
+			MaxStake:       *maxStake,
+			StateKey:       stateKey,
+			RngPool: rng.PoolConfig{
+				Low:     *rngPoolLow,
+				High:    *rngPoolHigh,
+				Timeout: *rngTimeout,
+			},
+			RngPoolReport: *rngPoolReport,
+		}); err != nil {
 			log.Err(err).Msg("Failed to start roulette server")
+			os.Exit(1)
 		}
 	case "rng":
-		if err := rng.StartServer(*port); err != nil {
+		if err := rng.StartServer(rng.Config{
+			Port:           *port,
+			Algorithm:      *rngAlg,
+			ReseedInterval: *rngReseed,
+			ReseedPeriod:   *rngReseedPeriod,
+			AdminPort:      *adminPort,
+		}); err != nil {
 			log.Err(err).Msg("Failed to start RNG server")
+			os.Exit(1)
 		}
 	case "rtp":
 		log.Info().Msg("start run rtp")
 		numRounds, _ := strconv.Atoi(*numRounds)
-		game.CalculateRTP(numRounds, *rngAddr)
+		if _, err := game.CalculateRTP(numRounds, *rngAddr); err != nil {
+			log.Err(err).Msg("Failed to calculate RTP")
+			os.Exit(1)
+		}
 		log.Info().Msg("rtp over")
 		os.Exit(0)
 	case "gateway":
-        if err := gateway.Start(*port,
-                                *rouletteAddr,
-                                time.Duration(*betWindow)*time.Second,
-                                time.Duration(*pauseWindow)*time.Second); err != nil {
-        	log.Err(err).Msg("gateway exited with error")
-        }
+		if *cheats && *production {
+			log.Error().Msg("Cheats are not allowed in production")
+			os.Exit(1)
+		}
+		if err := gateway.Start(*port,
+			*rouletteAddr,
+			*rngAddr,
+			*cueDir,
+			*clips,
+			*clipVariant,
+			*storePath,
+			time.Duration(*betWindow)*time.Second,
+			time.Duration(*pauseWindow)*time.Second,
+			*cheats); err != nil {
+			log.Err(err).Msg("gateway exited with error")
+		}
+	case "verify":
+		if err := fair.VerifyCommand(*replyPath, *serverSeed, *clientSeed, *nonce, *rouletteAddr); err != nil {
+			log.Err(err).Msg("verify failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "replay":
+		if err := server.Replay(*roundLogPath, stateKey); err != nil {
+			log.Err(err).Msg("replay failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "recheck":
+		// 未指定 -table 时检查所有桌
+		filter := ""
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "table" {
+				filter = *table
+			}
+		})
+		kind, path := "roundLog", *roundLogPath
+		if path == "" && *storePath != "" {
+			kind, path = "store", *storePath
+		} else if path == "" {
+			kind, path = "history", *historyPath
+		}
+		if path == "" {
+			log.Error().Msg("recheck mode needs -roundLog, -store or -history")
+			os.Exit(1)
+		}
+		if err := server.Recheck(kind, path, filter, *auditRound); err != nil {
+			log.Err(err).Msg("recheck failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "session":
+		if err := server.ReplaySession(server.SessionConfig{
+			Addr: *rouletteAddr,
+			Path: *capturePath,
+			Pin:  *pin,
+		}); err != nil {
+			log.Err(err).Msg("session replay failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "audit":
+		recs, err := rng.QueryAudit(*auditPath, *table, *auditRound)
+		if err != nil {
+			log.Err(err).Msg("audit query failed")
+			os.Exit(1)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, rec := range recs {
+			enc.Encode(rec)
+		}
+		if len(recs) == 0 {
+			log.Warn().Str("table", *table).Uint64("round", *auditRound).Msg("no RNG draws recorded for round")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "rngtest":
+		var rs []int
+		for _, r := range strings.Split(*ranges, ",") {
+			if r = strings.TrimSpace(r); r == "" {
+				continue
+			}
+			n, err := strconv.Atoi(r)
+			if err != nil {
+				log.Error().Msg("Invalid range: " + r)
+				os.Exit(1)
+			}
+			rs = append(rs, n)
+		}
+		rep, err := rngtest.Run(rngtest.Config{
+			RngAddr: *rngAddr,
+			Samples: *samples,
+			Ranges:  rs,
+			Alpha:   *alpha,
+			Report:  *reportPath,
+			Rng: rng.Config{
+				Algorithm:      *rngAlg,
+				ReseedInterval: *rngReseed,
+				ReseedPeriod:   *rngReseedPeriod,
+			},
+		})
+		if err != nil {
+			log.Err(err).Msg("rngtest failed")
+			os.Exit(1)
+		}
+		rep.WriteText(os.Stdout)
+		if !rep.Pass {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	case "bridge":
+		addr := *listen
+		if addr == "" {
+			addr = ":" + *port
+		}
+		var origins []string
+		for _, o := range strings.Split(*cors, ",") {
+			if o = strings.TrimSpace(o); o != "" {
+				origins = append(origins, o)
+			}
+		}
+		if err := bridge.Start(bridge.Config{
+			Addr:    addr,
+			RngAddr: *rngAddr,
+			Origins: origins,
+			APIKey:  *apiKey,
+		}); err != nil {
+			log.Err(err).Msg("bridge exited with error")
+		}
 	default:
 		log.Error().Msg("Invalid mode: " + *mode)
 		os.Exit(1)
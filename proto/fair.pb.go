@@ -0,0 +1,637 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.4
+// source: proto/fair.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FairProof - provably fair data of one round
+type FairProof struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeedHash string                 `protobuf:"bytes,1,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"` // sha256(serverSeed), committed before the round
+	ClientSeed     string                 `protobuf:"bytes,2,opt,name=clientSeed,proto3" json:"clientSeed,omitempty"`         // supplied by the player
+	Nonce          uint64                 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`                  // round counter under this server seed
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FairProof) Reset() {
+	*x = FairProof{}
+	mi := &file_proto_fair_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FairProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FairProof) ProtoMessage() {}
+
+func (x *FairProof) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FairProof.ProtoReflect.Descriptor instead.
+func (*FairProof) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FairProof) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+func (x *FairProof) GetClientSeed() string {
+	if x != nil {
+		return x.ClientSeed
+	}
+	return ""
+}
+
+func (x *FairProof) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// RequestCommitment - ask for the current server seed hash
+type RequestCommitment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestCommitment) Reset() {
+	*x = RequestCommitment{}
+	mi := &file_proto_fair_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestCommitment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCommitment) ProtoMessage() {}
+
+func (x *RequestCommitment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCommitment.ProtoReflect.Descriptor instead.
+func (*RequestCommitment) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{1}
+}
+
+// ReplyCommitment - current server seed hash and the next nonce
+type ReplyCommitment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeedHash string                 `protobuf:"bytes,1,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"`
+	Nonce          uint64                 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplyCommitment) Reset() {
+	*x = ReplyCommitment{}
+	mi := &file_proto_fair_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyCommitment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyCommitment) ProtoMessage() {}
+
+func (x *ReplyCommitment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyCommitment.ProtoReflect.Descriptor instead.
+func (*ReplyCommitment) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReplyCommitment) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+func (x *ReplyCommitment) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// RequestRotateSeed - retire the current server seed
+type RequestRotateSeed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestRotateSeed) Reset() {
+	*x = RequestRotateSeed{}
+	mi := &file_proto_fair_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestRotateSeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRotateSeed) ProtoMessage() {}
+
+func (x *RequestRotateSeed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRotateSeed.ProtoReflect.Descriptor instead.
+func (*RequestRotateSeed) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{3}
+}
+
+// ReplyRotateSeed - the revealed server seed and the new commitment
+type ReplyRotateSeed struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeed         string                 `protobuf:"bytes,1,opt,name=serverSeed,proto3" json:"serverSeed,omitempty"`
+	ServerSeedHash     string                 `protobuf:"bytes,2,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"`
+	Rounds             uint64                 `protobuf:"varint,3,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	NextServerSeedHash string                 `protobuf:"bytes,4,opt,name=nextServerSeedHash,proto3" json:"nextServerSeedHash,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ReplyRotateSeed) Reset() {
+	*x = ReplyRotateSeed{}
+	mi := &file_proto_fair_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyRotateSeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyRotateSeed) ProtoMessage() {}
+
+func (x *ReplyRotateSeed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyRotateSeed.ProtoReflect.Descriptor instead.
+func (*ReplyRotateSeed) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReplyRotateSeed) GetServerSeed() string {
+	if x != nil {
+		return x.ServerSeed
+	}
+	return ""
+}
+
+func (x *ReplyRotateSeed) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+func (x *ReplyRotateSeed) GetRounds() uint64 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *ReplyRotateSeed) GetNextServerSeedHash() string {
+	if x != nil {
+		return x.NextServerSeedHash
+	}
+	return ""
+}
+
+// RequestRevealSeed - ask for a retired server seed
+type RequestRevealSeed struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeedHash string                 `protobuf:"bytes,1,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RequestRevealSeed) Reset() {
+	*x = RequestRevealSeed{}
+	mi := &file_proto_fair_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestRevealSeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRevealSeed) ProtoMessage() {}
+
+func (x *RequestRevealSeed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRevealSeed.ProtoReflect.Descriptor instead.
+func (*RequestRevealSeed) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RequestRevealSeed) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+// ReplyRevealSeed - a retired server seed
+type ReplyRevealSeed struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeed     string                 `protobuf:"bytes,1,opt,name=serverSeed,proto3" json:"serverSeed,omitempty"`
+	ServerSeedHash string                 `protobuf:"bytes,2,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"`
+	Rounds         uint64                 `protobuf:"varint,3,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplyRevealSeed) Reset() {
+	*x = ReplyRevealSeed{}
+	mi := &file_proto_fair_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyRevealSeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyRevealSeed) ProtoMessage() {}
+
+func (x *ReplyRevealSeed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyRevealSeed.ProtoReflect.Descriptor instead.
+func (*ReplyRevealSeed) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReplyRevealSeed) GetServerSeed() string {
+	if x != nil {
+		return x.ServerSeed
+	}
+	return ""
+}
+
+func (x *ReplyRevealSeed) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+func (x *ReplyRevealSeed) GetRounds() uint64 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+// RequestVerify - recompute an outcome
+//
+//	serverSeed may be empty when serverSeedHash names a retired seed
+type RequestVerify struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeed     string                 `protobuf:"bytes,1,opt,name=serverSeed,proto3" json:"serverSeed,omitempty"`
+	ServerSeedHash string                 `protobuf:"bytes,2,opt,name=serverSeedHash,proto3" json:"serverSeedHash,omitempty"`
+	ClientSeed     string                 `protobuf:"bytes,3,opt,name=clientSeed,proto3" json:"clientSeed,omitempty"`
+	Nonce          uint64                 `protobuf:"varint,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RequestVerify) Reset() {
+	*x = RequestVerify{}
+	mi := &file_proto_fair_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestVerify) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestVerify) ProtoMessage() {}
+
+func (x *RequestVerify) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestVerify.ProtoReflect.Descriptor instead.
+func (*RequestVerify) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RequestVerify) GetServerSeed() string {
+	if x != nil {
+		return x.ServerSeed
+	}
+	return ""
+}
+
+func (x *RequestVerify) GetServerSeedHash() string {
+	if x != nil {
+		return x.ServerSeedHash
+	}
+	return ""
+}
+
+func (x *RequestVerify) GetClientSeed() string {
+	if x != nil {
+		return x.ClientSeed
+	}
+	return ""
+}
+
+func (x *RequestVerify) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// ReplyVerify - recomputed outcome
+type ReplyVerify struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WinningNumber int32                  `protobuf:"varint,1,opt,name=winningNumber,proto3" json:"winningNumber,omitempty"`
+	ServerSeed    string                 `protobuf:"bytes,2,opt,name=serverSeed,proto3" json:"serverSeed,omitempty"`
+	HashMatches   bool                   `protobuf:"varint,3,opt,name=hashMatches,proto3" json:"hashMatches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyVerify) Reset() {
+	*x = ReplyVerify{}
+	mi := &file_proto_fair_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyVerify) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyVerify) ProtoMessage() {}
+
+func (x *ReplyVerify) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fair_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyVerify.ProtoReflect.Descriptor instead.
+func (*ReplyVerify) Descriptor() ([]byte, []int) {
+	return file_proto_fair_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReplyVerify) GetWinningNumber() int32 {
+	if x != nil {
+		return x.WinningNumber
+	}
+	return 0
+}
+
+func (x *ReplyVerify) GetServerSeed() string {
+	if x != nil {
+		return x.ServerSeed
+	}
+	return ""
+}
+
+func (x *ReplyVerify) GetHashMatches() bool {
+	if x != nil {
+		return x.HashMatches
+	}
+	return false
+}
+
+var File_proto_fair_proto protoreflect.FileDescriptor
+
+const file_proto_fair_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/fair.proto\x12\x06sgc7pb\"i\n" +
+	"\tFairProof\x12&\n" +
+	"\x0eserverSeedHash\x18\x01 \x01(\tR\x0eserverSeedHash\x12\x1e\n" +
+	"\n" +
+	"clientSeed\x18\x02 \x01(\tR\n" +
+	"clientSeed\x12\x14\n" +
+	"\x05nonce\x18\x03 \x01(\x04R\x05nonce\"\x13\n" +
+	"\x11RequestCommitment\"O\n" +
+	"\x0fReplyCommitment\x12&\n" +
+	"\x0eserverSeedHash\x18\x01 \x01(\tR\x0eserverSeedHash\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\x04R\x05nonce\"\x13\n" +
+	"\x11RequestRotateSeed\"\xa1\x01\n" +
+	"\x0fReplyRotateSeed\x12\x1e\n" +
+	"\n" +
+	"serverSeed\x18\x01 \x01(\tR\n" +
+	"serverSeed\x12&\n" +
+	"\x0eserverSeedHash\x18\x02 \x01(\tR\x0eserverSeedHash\x12\x16\n" +
+	"\x06rounds\x18\x03 \x01(\x04R\x06rounds\x12.\n" +
+	"\x12nextServerSeedHash\x18\x04 \x01(\tR\x12nextServerSeedHash\";\n" +
+	"\x11RequestRevealSeed\x12&\n" +
+	"\x0eserverSeedHash\x18\x01 \x01(\tR\x0eserverSeedHash\"q\n" +
+	"\x0fReplyRevealSeed\x12\x1e\n" +
+	"\n" +
+	"serverSeed\x18\x01 \x01(\tR\n" +
+	"serverSeed\x12&\n" +
+	"\x0eserverSeedHash\x18\x02 \x01(\tR\x0eserverSeedHash\x12\x16\n" +
+	"\x06rounds\x18\x03 \x01(\x04R\x06rounds\"\x8d\x01\n" +
+	"\rRequestVerify\x12\x1e\n" +
+	"\n" +
+	"serverSeed\x18\x01 \x01(\tR\n" +
+	"serverSeed\x12&\n" +
+	"\x0eserverSeedHash\x18\x02 \x01(\tR\x0eserverSeedHash\x12\x1e\n" +
+	"\n" +
+	"clientSeed\x18\x03 \x01(\tR\n" +
+	"clientSeed\x12\x14\n" +
+	"\x05nonce\x18\x04 \x01(\x04R\x05nonce\"u\n" +
+	"\vReplyVerify\x12$\n" +
+	"\rwinningNumber\x18\x01 \x01(\x05R\rwinningNumber\x12\x1e\n" +
+	"\n" +
+	"serverSeed\x18\x02 \x01(\tR\n" +
+	"serverSeed\x12 \n" +
+	"\vhashMatches\x18\x03 \x01(\bR\vhashMatches2\xc6\x01\n" +
+	"\x04Fair\x12B\n" +
+	"\n" +
+	"commitment\x12\x19.sgc7pb.RequestCommitment\x1a\x17.sgc7pb.ReplyCommitment\"\x00\x12B\n" +
+	"\n" +
+	"revealSeed\x12\x19.sgc7pb.RequestRevealSeed\x1a\x17.sgc7pb.ReplyRevealSeed\"\x00\x126\n" +
+	"\x06verify\x12\x15.sgc7pb.RequestVerify\x1a\x13.sgc7pb.ReplyVerify\"\x002O\n" +
+	"\tFairAdmin\x12B\n" +
+	"\n" +
+	"rotateSeed\x12\x19.sgc7pb.RequestRotateSeed\x1a\x17.sgc7pb.ReplyRotateSeed\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
+
+var (
+	file_proto_fair_proto_rawDescOnce sync.Once
+	file_proto_fair_proto_rawDescData []byte
+)
+
+func file_proto_fair_proto_rawDescGZIP() []byte {
+	file_proto_fair_proto_rawDescOnce.Do(func() {
+		file_proto_fair_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_fair_proto_rawDesc), len(file_proto_fair_proto_rawDesc)))
+	})
+	return file_proto_fair_proto_rawDescData
+}
+
+var file_proto_fair_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_fair_proto_goTypes = []any{
+	(*FairProof)(nil),         // 0: sgc7pb.FairProof
+	(*RequestCommitment)(nil), // 1: sgc7pb.RequestCommitment
+	(*ReplyCommitment)(nil),   // 2: sgc7pb.ReplyCommitment
+	(*RequestRotateSeed)(nil), // 3: sgc7pb.RequestRotateSeed
+	(*ReplyRotateSeed)(nil),   // 4: sgc7pb.ReplyRotateSeed
+	(*RequestRevealSeed)(nil), // 5: sgc7pb.RequestRevealSeed
+	(*ReplyRevealSeed)(nil),   // 6: sgc7pb.ReplyRevealSeed
+	(*RequestVerify)(nil),     // 7: sgc7pb.RequestVerify
+	(*ReplyVerify)(nil),       // 8: sgc7pb.ReplyVerify
+}
+var file_proto_fair_proto_depIdxs = []int32{
+	1, // 0: sgc7pb.Fair.commitment:input_type -> sgc7pb.RequestCommitment
+	5, // 1: sgc7pb.Fair.revealSeed:input_type -> sgc7pb.RequestRevealSeed
+	7, // 2: sgc7pb.Fair.verify:input_type -> sgc7pb.RequestVerify
+	3, // 3: sgc7pb.FairAdmin.rotateSeed:input_type -> sgc7pb.RequestRotateSeed
+	2, // 4: sgc7pb.Fair.commitment:output_type -> sgc7pb.ReplyCommitment
+	6, // 5: sgc7pb.Fair.revealSeed:output_type -> sgc7pb.ReplyRevealSeed
+	8, // 6: sgc7pb.Fair.verify:output_type -> sgc7pb.ReplyVerify
+	4, // 7: sgc7pb.FairAdmin.rotateSeed:output_type -> sgc7pb.ReplyRotateSeed
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_fair_proto_init() }
+func file_proto_fair_proto_init() {
+	if File_proto_fair_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_fair_proto_rawDesc), len(file_proto_fair_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_proto_fair_proto_goTypes,
+		DependencyIndexes: file_proto_fair_proto_depIdxs,
+		MessageInfos:      file_proto_fair_proto_msgTypes,
+	}.Build()
+	File_proto_fair_proto = out.File
+	file_proto_fair_proto_goTypes = nil
+	file_proto_fair_proto_depIdxs = nil
+}
@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.4
+// source: proto/fair.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Fair_Commitment_FullMethodName = "/sgc7pb.Fair/commitment"
+	Fair_RevealSeed_FullMethodName = "/sgc7pb.Fair/revealSeed"
+	Fair_Verify_FullMethodName     = "/sgc7pb.Fair/verify"
+)
+
+// FairClient is the client API for Fair service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Fair - provably fair commitments and verification, public
+type FairClient interface {
+	// commitment - current server seed hash
+	Commitment(ctx context.Context, in *RequestCommitment, opts ...grpc.CallOption) (*ReplyCommitment, error)
+	// revealSeed - get a retired server seed
+	RevealSeed(ctx context.Context, in *RequestRevealSeed, opts ...grpc.CallOption) (*ReplyRevealSeed, error)
+	// verify - recompute the winning number of a round
+	Verify(ctx context.Context, in *RequestVerify, opts ...grpc.CallOption) (*ReplyVerify, error)
+}
+
+type fairClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFairClient(cc grpc.ClientConnInterface) FairClient {
+	return &fairClient{cc}
+}
+
+func (c *fairClient) Commitment(ctx context.Context, in *RequestCommitment, opts ...grpc.CallOption) (*ReplyCommitment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyCommitment)
+	err := c.cc.Invoke(ctx, Fair_Commitment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fairClient) RevealSeed(ctx context.Context, in *RequestRevealSeed, opts ...grpc.CallOption) (*ReplyRevealSeed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyRevealSeed)
+	err := c.cc.Invoke(ctx, Fair_RevealSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fairClient) Verify(ctx context.Context, in *RequestVerify, opts ...grpc.CallOption) (*ReplyVerify, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyVerify)
+	err := c.cc.Invoke(ctx, Fair_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FairServer is the server API for Fair service.
+// All implementations must embed UnimplementedFairServer
+// for forward compatibility.
+//
+// Fair - provably fair commitments and verification, public
+type FairServer interface {
+	// commitment - current server seed hash
+	Commitment(context.Context, *RequestCommitment) (*ReplyCommitment, error)
+	// revealSeed - get a retired server seed
+	RevealSeed(context.Context, *RequestRevealSeed) (*ReplyRevealSeed, error)
+	// verify - recompute the winning number of a round
+	Verify(context.Context, *RequestVerify) (*ReplyVerify, error)
+	mustEmbedUnimplementedFairServer()
+}
+
+// UnimplementedFairServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFairServer struct{}
+
+func (UnimplementedFairServer) Commitment(context.Context, *RequestCommitment) (*ReplyCommitment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commitment not implemented")
+}
+func (UnimplementedFairServer) RevealSeed(context.Context, *RequestRevealSeed) (*ReplyRevealSeed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevealSeed not implemented")
+}
+func (UnimplementedFairServer) Verify(context.Context, *RequestVerify) (*ReplyVerify, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedFairServer) mustEmbedUnimplementedFairServer() {}
+func (UnimplementedFairServer) testEmbeddedByValue()              {}
+
+// UnsafeFairServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FairServer will
+// result in compilation errors.
+type UnsafeFairServer interface {
+	mustEmbedUnimplementedFairServer()
+}
+
+func RegisterFairServer(s grpc.ServiceRegistrar, srv FairServer) {
+	// If the following call pancis, it indicates UnimplementedFairServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Fair_ServiceDesc, srv)
+}
+
+func _Fair_Commitment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestCommitment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FairServer).Commitment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Fair_Commitment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FairServer).Commitment(ctx, req.(*RequestCommitment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fair_RevealSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRevealSeed)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FairServer).RevealSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Fair_RevealSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FairServer).RevealSeed(ctx, req.(*RequestRevealSeed))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fair_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestVerify)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FairServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Fair_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FairServer).Verify(ctx, req.(*RequestVerify))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Fair_ServiceDesc is the grpc.ServiceDesc for Fair service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Fair_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sgc7pb.Fair",
+	HandlerType: (*FairServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "commitment",
+			Handler:    _Fair_Commitment_Handler,
+		},
+		{
+			MethodName: "revealSeed",
+			Handler:    _Fair_RevealSeed_Handler,
+		},
+		{
+			MethodName: "verify",
+			Handler:    _Fair_Verify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/fair.proto",
+}
+
+const (
+	FairAdmin_RotateSeed_FullMethodName = "/sgc7pb.FairAdmin/rotateSeed"
+)
+
+// FairAdminClient is the client API for FairAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FairAdmin - provably fair seed rotation, served on the admin port only
+type FairAdminClient interface {
+	// rotateSeed - reveal the current server seed and commit to a new one
+	RotateSeed(ctx context.Context, in *RequestRotateSeed, opts ...grpc.CallOption) (*ReplyRotateSeed, error)
+}
+
+type fairAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFairAdminClient(cc grpc.ClientConnInterface) FairAdminClient {
+	return &fairAdminClient{cc}
+}
+
+func (c *fairAdminClient) RotateSeed(ctx context.Context, in *RequestRotateSeed, opts ...grpc.CallOption) (*ReplyRotateSeed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyRotateSeed)
+	err := c.cc.Invoke(ctx, FairAdmin_RotateSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FairAdminServer is the server API for FairAdmin service.
+// All implementations must embed UnimplementedFairAdminServer
+// for forward compatibility.
+//
+// FairAdmin - provably fair seed rotation, served on the admin port only
+type FairAdminServer interface {
+	// rotateSeed - reveal the current server seed and commit to a new one
+	RotateSeed(context.Context, *RequestRotateSeed) (*ReplyRotateSeed, error)
+	mustEmbedUnimplementedFairAdminServer()
+}
+
+// UnimplementedFairAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFairAdminServer struct{}
+
+func (UnimplementedFairAdminServer) RotateSeed(context.Context, *RequestRotateSeed) (*ReplyRotateSeed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSeed not implemented")
+}
+func (UnimplementedFairAdminServer) mustEmbedUnimplementedFairAdminServer() {}
+func (UnimplementedFairAdminServer) testEmbeddedByValue()                   {}
+
+// UnsafeFairAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FairAdminServer will
+// result in compilation errors.
+type UnsafeFairAdminServer interface {
+	mustEmbedUnimplementedFairAdminServer()
+}
+
+func RegisterFairAdminServer(s grpc.ServiceRegistrar, srv FairAdminServer) {
+	// If the following call pancis, it indicates UnimplementedFairAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FairAdmin_ServiceDesc, srv)
+}
+
+func _FairAdmin_RotateSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRotateSeed)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FairAdminServer).RotateSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FairAdmin_RotateSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FairAdminServer).RotateSeed(ctx, req.(*RequestRotateSeed))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FairAdmin_ServiceDesc is the grpc.ServiceDesc for FairAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FairAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sgc7pb.FairAdmin",
+	HandlerType: (*FairAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "rotateSeed",
+			Handler:    _FairAdmin_RotateSeed_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/fair.proto",
+}
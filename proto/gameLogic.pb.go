@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.11
-// 	protoc        v4.23.4
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.4
 // source: proto/gameLogic.proto
 
 package proto
@@ -482,6 +482,7 @@ type RequestPlay struct {
 	Stake         *Stake                 `protobuf:"bytes,3,opt,name=stake,proto3" json:"stake,omitempty"`
 	ClientParams  string                 `protobuf:"bytes,4,opt,name=clientParams,proto3" json:"clientParams,omitempty"`
 	Command       string                 `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
+	RoundId       string                 `protobuf:"bytes,6,opt,name=roundId,proto3" json:"roundId,omitempty"` // 幂等键, 同一键的重复请求返回第一次的结果
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -551,19 +552,74 @@ func (x *RequestPlay) GetCommand() string {
 	return ""
 }
 
+func (x *RequestPlay) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+// RequestResult - 按幂等键查询已完成的局
+type RequestResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoundId       string                 `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResult) Reset() {
+	*x = RequestResult{}
+	mi := &file_proto_gameLogic_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResult) ProtoMessage() {}
+
+func (x *RequestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gameLogic_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResult.ProtoReflect.Descriptor instead.
+func (*RequestResult) Descriptor() ([]byte, []int) {
+	return file_proto_gameLogic_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RequestResult) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
 // RngInfo - rng infomation
 type RngInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Bits          int32                  `protobuf:"varint,1,opt,name=bits,proto3" json:"bits,omitempty"`
-	Range         int32                  `protobuf:"varint,2,opt,name=range,proto3" json:"range,omitempty"`
-	Value         int32                  `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	Bits          int32                  `protobuf:"varint,1,opt,name=bits,proto3" json:"bits,omitempty"`                // 原始随机数位数, 32
+	Range         int32                  `protobuf:"varint,2,opt,name=range,proto3" json:"range,omitempty"`              // 缩放范围, value = raw % range
+	Value         int32                  `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`              // 缩放结果
+	Raw           uint32                 `protobuf:"varint,4,opt,name=raw,proto3" json:"raw,omitempty"`                  // 被采用的原始随机数
+	Rejected      []uint32               `protobuf:"varint,5,rep,packed,name=rejected,proto3" json:"rejected,omitempty"` // 在 raw 之前因无偏缩放被拒绝的原始随机数
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`             // rng、local、seeded 或 fair
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RngInfo) Reset() {
 	*x = RngInfo{}
-	mi := &file_proto_gameLogic_proto_msgTypes[10]
+	mi := &file_proto_gameLogic_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -575,7 +631,7 @@ func (x *RngInfo) String() string {
 func (*RngInfo) ProtoMessage() {}
 
 func (x *RngInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gameLogic_proto_msgTypes[10]
+	mi := &file_proto_gameLogic_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,7 +644,7 @@ func (x *RngInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RngInfo.ProtoReflect.Descriptor instead.
 func (*RngInfo) Descriptor() ([]byte, []int) {
-	return file_proto_gameLogic_proto_rawDescGZIP(), []int{10}
+	return file_proto_gameLogic_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RngInfo) GetBits() int32 {
@@ -612,6 +668,27 @@ func (x *RngInfo) GetValue() int32 {
 	return 0
 }
 
+func (x *RngInfo) GetRaw() uint32 {
+	if x != nil {
+		return x.Raw
+	}
+	return 0
+}
+
+func (x *RngInfo) GetRejected() []uint32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+func (x *RngInfo) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
 // PlayResult - result for play
 type PlayResult struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -623,7 +700,7 @@ type PlayResult struct {
 
 func (x *PlayResult) Reset() {
 	*x = PlayResult{}
-	mi := &file_proto_gameLogic_proto_msgTypes[11]
+	mi := &file_proto_gameLogic_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -635,7 +712,7 @@ func (x *PlayResult) String() string {
 func (*PlayResult) ProtoMessage() {}
 
 func (x *PlayResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gameLogic_proto_msgTypes[11]
+	mi := &file_proto_gameLogic_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -648,7 +725,7 @@ func (x *PlayResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlayResult.ProtoReflect.Descriptor instead.
 func (*PlayResult) Descriptor() ([]byte, []int) {
-	return file_proto_gameLogic_proto_rawDescGZIP(), []int{11}
+	return file_proto_gameLogic_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *PlayResult) GetCurGameMod() string {
@@ -679,7 +756,7 @@ type GameResult struct {
 
 func (x *GameResult) Reset() {
 	*x = GameResult{}
-	mi := &file_proto_gameLogic_proto_msgTypes[12]
+	mi := &file_proto_gameLogic_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -691,7 +768,7 @@ func (x *GameResult) String() string {
 func (*GameResult) ProtoMessage() {}
 
 func (x *GameResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gameLogic_proto_msgTypes[12]
+	mi := &file_proto_gameLogic_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -704,7 +781,7 @@ func (x *GameResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameResult.ProtoReflect.Descriptor instead.
 func (*GameResult) Descriptor() ([]byte, []int) {
-	return file_proto_gameLogic_proto_rawDescGZIP(), []int{12}
+	return file_proto_gameLogic_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GameResult) GetCoinWin() int64 {
@@ -737,13 +814,15 @@ type ReplyPlay struct {
 	Results           []*GameResult          `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
 	NextCommands      []string               `protobuf:"bytes,5,rep,name=nextCommands,proto3" json:"nextCommands,omitempty"`
 	NextCommandParams []string               `protobuf:"bytes,7,rep,name=nextCommandParams,proto3" json:"nextCommandParams,omitempty"`
+	RoundId           string                 `protobuf:"bytes,8,opt,name=roundId,proto3" json:"roundId,omitempty"` // 请求中的幂等键
+	Round             uint64                 `protobuf:"varint,9,opt,name=round,proto3" json:"round,omitempty"`    // 服务端局号
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *ReplyPlay) Reset() {
 	*x = ReplyPlay{}
-	mi := &file_proto_gameLogic_proto_msgTypes[13]
+	mi := &file_proto_gameLogic_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -755,7 +834,7 @@ func (x *ReplyPlay) String() string {
 func (*ReplyPlay) ProtoMessage() {}
 
 func (x *ReplyPlay) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gameLogic_proto_msgTypes[13]
+	mi := &file_proto_gameLogic_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -768,7 +847,7 @@ func (x *ReplyPlay) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplyPlay.ProtoReflect.Descriptor instead.
 func (*ReplyPlay) Descriptor() ([]byte, []int) {
-	return file_proto_gameLogic_proto_rawDescGZIP(), []int{13}
+	return file_proto_gameLogic_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ReplyPlay) GetRandomNumbers() []*RngInfo {
@@ -813,6 +892,20 @@ func (x *ReplyPlay) GetNextCommandParams() []string {
 	return nil
 }
 
+func (x *ReplyPlay) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *ReplyPlay) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
 var File_proto_gameLogic_proto protoreflect.FileDescriptor
 
 const file_proto_gameLogic_proto_rawDesc = "" +
@@ -842,17 +935,23 @@ const file_proto_gameLogic_proto_rawDesc = "" +
 	"\x05Stake\x12\x18\n" +
 	"\acoinBet\x18\x01 \x01(\x03R\acoinBet\x12\x18\n" +
 	"\acashBet\x18\x02 \x01(\x03R\acashBet\x12\x1a\n" +
-	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xbd\x01\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xd7\x01\n" +
 	"\vRequestPlay\x125\n" +
 	"\vplayerState\x18\x01 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\x12\x14\n" +
 	"\x05cheat\x18\x02 \x01(\tR\x05cheat\x12#\n" +
 	"\x05stake\x18\x03 \x01(\v2\r.sgc7pb.StakeR\x05stake\x12\"\n" +
 	"\fclientParams\x18\x04 \x01(\tR\fclientParams\x12\x18\n" +
-	"\acommand\x18\x05 \x01(\tR\acommand\"I\n" +
+	"\acommand\x18\x05 \x01(\tR\acommand\x12\x18\n" +
+	"\aroundId\x18\x06 \x01(\tR\aroundId\")\n" +
+	"\rRequestResult\x12\x18\n" +
+	"\aroundId\x18\x01 \x01(\tR\aroundId\"\x8f\x01\n" +
 	"\aRngInfo\x12\x12\n" +
 	"\x04bits\x18\x01 \x01(\x05R\x04bits\x12\x14\n" +
 	"\x05range\x18\x02 \x01(\x05R\x05range\x12\x14\n" +
-	"\x05value\x18\x03 \x01(\x05R\x05value\"l\n" +
+	"\x05value\x18\x03 \x01(\x05R\x05value\x12\x10\n" +
+	"\x03raw\x18\x04 \x01(\rR\x03raw\x12\x1a\n" +
+	"\brejected\x18\x05 \x03(\rR\brejected\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\"l\n" +
 	"\n" +
 	"PlayResult\x12\x1e\n" +
 	"\n" +
@@ -865,21 +964,24 @@ const file_proto_gameLogic_proto_rawDesc = "" +
 	"\acashWin\x18\x02 \x01(\x03R\acashWin\x122\n" +
 	"\n" +
 	"clientData\x18\x03 \x01(\v2\x12.sgc7pb.PlayResultR\n" +
-	"clientData\"\x95\x02\n" +
+	"clientData\"\xc5\x02\n" +
 	"\tReplyPlay\x125\n" +
 	"\rrandomNumbers\x18\x01 \x03(\v2\x0f.sgc7pb.RngInfoR\rrandomNumbers\x125\n" +
 	"\vplayerState\x18\x02 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\x12\x1a\n" +
 	"\bfinished\x18\x03 \x01(\bR\bfinished\x12,\n" +
 	"\aresults\x18\x04 \x03(\v2\x12.sgc7pb.GameResultR\aresults\x12\"\n" +
 	"\fnextCommands\x18\x05 \x03(\tR\fnextCommands\x12,\n" +
-	"\x11nextCommandParams\x18\a \x03(\tR\x11nextCommandParams2T\n" +
+	"\x11nextCommandParams\x18\a \x03(\tR\x11nextCommandParams\x12\x18\n" +
+	"\aroundId\x18\b \x01(\tR\aroundId\x12\x14\n" +
+	"\x05round\x18\t \x01(\x04R\x05round2T\n" +
 	"\vTestService\x12E\n" +
-	"\vTestBackend\x12\x1a.sgc7pb.TestBackendRequest\x1a\x18.sgc7pb.TestBackendReply\"\x002\xb8\x01\n" +
+	"\vTestBackend\x12\x1a.sgc7pb.TestBackendRequest\x1a\x18.sgc7pb.TestBackendReply\"\x002\xf1\x01\n" +
 	"\tGameLogic\x128\n" +
 	"\tgetConfig\x12\x15.sgc7pb.RequestConfig\x1a\x12.sgc7pb.GameConfig\"\x00\x12>\n" +
 	"\n" +
 	"initialize\x12\x19.sgc7pb.RequestInitialize\x1a\x13.sgc7pb.PlayerState\"\x00\x121\n" +
-	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
+	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x00\x127\n" +
+	"\tgetResult\x12\x15.sgc7pb.RequestResult\x1a\x11.sgc7pb.ReplyPlay\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
 
 var (
 	file_proto_gameLogic_proto_rawDescOnce sync.Once
@@ -893,7 +995,7 @@ func file_proto_gameLogic_proto_rawDescGZIP() []byte {
 	return file_proto_gameLogic_proto_rawDescData
 }
 
-var file_proto_gameLogic_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_gameLogic_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_proto_gameLogic_proto_goTypes = []any{
 	(*Column)(nil),             // 0: sgc7pb.Column
 	(*TestBackendRequest)(nil), // 1: sgc7pb.TestBackendRequest
@@ -905,34 +1007,37 @@ var file_proto_gameLogic_proto_goTypes = []any{
 	(*RequestInitialize)(nil),  // 7: sgc7pb.RequestInitialize
 	(*Stake)(nil),              // 8: sgc7pb.Stake
 	(*RequestPlay)(nil),        // 9: sgc7pb.RequestPlay
-	(*RngInfo)(nil),            // 10: sgc7pb.RngInfo
-	(*PlayResult)(nil),         // 11: sgc7pb.PlayResult
-	(*GameResult)(nil),         // 12: sgc7pb.GameResult
-	(*ReplyPlay)(nil),          // 13: sgc7pb.ReplyPlay
-	(*anypb.Any)(nil),          // 14: google.protobuf.Any
+	(*RequestResult)(nil),      // 10: sgc7pb.RequestResult
+	(*RngInfo)(nil),            // 11: sgc7pb.RngInfo
+	(*PlayResult)(nil),         // 12: sgc7pb.PlayResult
+	(*GameResult)(nil),         // 13: sgc7pb.GameResult
+	(*ReplyPlay)(nil),          // 14: sgc7pb.ReplyPlay
+	(*anypb.Any)(nil),          // 15: google.protobuf.Any
 }
 var file_proto_gameLogic_proto_depIdxs = []int32{
 	0,  // 0: sgc7pb.GameScene.values:type_name -> sgc7pb.Column
 	3,  // 1: sgc7pb.GameConfig.defaultScene:type_name -> sgc7pb.GameScene
-	14, // 2: sgc7pb.PlayerState.public:type_name -> google.protobuf.Any
-	14, // 3: sgc7pb.PlayerState.private:type_name -> google.protobuf.Any
+	15, // 2: sgc7pb.PlayerState.public:type_name -> google.protobuf.Any
+	15, // 3: sgc7pb.PlayerState.private:type_name -> google.protobuf.Any
 	6,  // 4: sgc7pb.RequestPlay.playerState:type_name -> sgc7pb.PlayerState
 	8,  // 5: sgc7pb.RequestPlay.stake:type_name -> sgc7pb.Stake
-	14, // 6: sgc7pb.PlayResult.curGameModParam:type_name -> google.protobuf.Any
-	11, // 7: sgc7pb.GameResult.clientData:type_name -> sgc7pb.PlayResult
-	10, // 8: sgc7pb.ReplyPlay.randomNumbers:type_name -> sgc7pb.RngInfo
+	15, // 6: sgc7pb.PlayResult.curGameModParam:type_name -> google.protobuf.Any
+	12, // 7: sgc7pb.GameResult.clientData:type_name -> sgc7pb.PlayResult
+	11, // 8: sgc7pb.ReplyPlay.randomNumbers:type_name -> sgc7pb.RngInfo
 	6,  // 9: sgc7pb.ReplyPlay.playerState:type_name -> sgc7pb.PlayerState
-	12, // 10: sgc7pb.ReplyPlay.results:type_name -> sgc7pb.GameResult
+	13, // 10: sgc7pb.ReplyPlay.results:type_name -> sgc7pb.GameResult
 	1,  // 11: sgc7pb.TestService.TestBackend:input_type -> sgc7pb.TestBackendRequest
 	5,  // 12: sgc7pb.GameLogic.getConfig:input_type -> sgc7pb.RequestConfig
 	7,  // 13: sgc7pb.GameLogic.initialize:input_type -> sgc7pb.RequestInitialize
 	9,  // 14: sgc7pb.GameLogic.play2:input_type -> sgc7pb.RequestPlay
-	2,  // 15: sgc7pb.TestService.TestBackend:output_type -> sgc7pb.TestBackendReply
-	4,  // 16: sgc7pb.GameLogic.getConfig:output_type -> sgc7pb.GameConfig
-	6,  // 17: sgc7pb.GameLogic.initialize:output_type -> sgc7pb.PlayerState
-	13, // 18: sgc7pb.GameLogic.play2:output_type -> sgc7pb.ReplyPlay
-	15, // [15:19] is the sub-list for method output_type
-	11, // [11:15] is the sub-list for method input_type
+	10, // 15: sgc7pb.GameLogic.getResult:input_type -> sgc7pb.RequestResult
+	2,  // 16: sgc7pb.TestService.TestBackend:output_type -> sgc7pb.TestBackendReply
+	4,  // 17: sgc7pb.GameLogic.getConfig:output_type -> sgc7pb.GameConfig
+	6,  // 18: sgc7pb.GameLogic.initialize:output_type -> sgc7pb.PlayerState
+	14, // 19: sgc7pb.GameLogic.play2:output_type -> sgc7pb.ReplyPlay
+	14, // 20: sgc7pb.GameLogic.getResult:output_type -> sgc7pb.ReplyPlay
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
 	11, // [11:11] is the sub-list for extension type_name
 	11, // [11:11] is the sub-list for extension extendee
 	0,  // [0:11] is the sub-list for field type_name
@@ -949,7 +1054,7 @@ func file_proto_gameLogic_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gameLogic_proto_rawDesc), len(file_proto_gameLogic_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
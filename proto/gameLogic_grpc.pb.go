@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.6.0
-// - protoc             v4.23.4
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.4
 // source: proto/gameLogic.proto
 
 package proto
@@ -67,7 +67,7 @@ type TestServiceServer interface {
 type UnimplementedTestServiceServer struct{}
 
 func (UnimplementedTestServiceServer) TestBackend(context.Context, *TestBackendRequest) (*TestBackendReply, error) {
-	return nil, status.Error(codes.Unimplemented, "method TestBackend not implemented")
+	return nil, status.Errorf(codes.Unimplemented, "method TestBackend not implemented")
 }
 func (UnimplementedTestServiceServer) mustEmbedUnimplementedTestServiceServer() {}
 func (UnimplementedTestServiceServer) testEmbeddedByValue()                     {}
@@ -80,7 +80,7 @@ type UnsafeTestServiceServer interface {
 }
 
 func RegisterTestServiceServer(s grpc.ServiceRegistrar, srv TestServiceServer) {
-	// If the following call panics, it indicates UnimplementedTestServiceServer was
+	// If the following call pancis, it indicates UnimplementedTestServiceServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
@@ -128,6 +128,7 @@ const (
 	GameLogic_GetConfig_FullMethodName  = "/sgc7pb.GameLogic/getConfig"
 	GameLogic_Initialize_FullMethodName = "/sgc7pb.GameLogic/initialize"
 	GameLogic_Play2_FullMethodName      = "/sgc7pb.GameLogic/play2"
+	GameLogic_GetResult_FullMethodName  = "/sgc7pb.GameLogic/getResult"
 )
 
 // GameLogicClient is the client API for GameLogic service.
@@ -142,6 +143,8 @@ type GameLogicClient interface {
 	Initialize(ctx context.Context, in *RequestInitialize, opts ...grpc.CallOption) (*PlayerState, error)
 	// play2 - play game v2
 	Play2(ctx context.Context, in *RequestPlay, opts ...grpc.CallOption) (*ReplyPlay, error)
+	// getResult - 查询保留时间内按 roundId 完成的局, 不开局
+	GetResult(ctx context.Context, in *RequestResult, opts ...grpc.CallOption) (*ReplyPlay, error)
 }
 
 type gameLogicClient struct {
@@ -182,6 +185,16 @@ func (c *gameLogicClient) Play2(ctx context.Context, in *RequestPlay, opts ...gr
 	return out, nil
 }
 
+func (c *gameLogicClient) GetResult(ctx context.Context, in *RequestResult, opts ...grpc.CallOption) (*ReplyPlay, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyPlay)
+	err := c.cc.Invoke(ctx, GameLogic_GetResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GameLogicServer is the server API for GameLogic service.
 // All implementations must embed UnimplementedGameLogicServer
 // for forward compatibility.
@@ -194,6 +207,8 @@ type GameLogicServer interface {
 	Initialize(context.Context, *RequestInitialize) (*PlayerState, error)
 	// play2 - play game v2
 	Play2(context.Context, *RequestPlay) (*ReplyPlay, error)
+	// getResult - 查询保留时间内按 roundId 完成的局, 不开局
+	GetResult(context.Context, *RequestResult) (*ReplyPlay, error)
 	mustEmbedUnimplementedGameLogicServer()
 }
 
@@ -205,13 +220,16 @@ type GameLogicServer interface {
 type UnimplementedGameLogicServer struct{}
 
 func (UnimplementedGameLogicServer) GetConfig(context.Context, *RequestConfig) (*GameConfig, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetConfig not implemented")
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
 }
 func (UnimplementedGameLogicServer) Initialize(context.Context, *RequestInitialize) (*PlayerState, error) {
-	return nil, status.Error(codes.Unimplemented, "method Initialize not implemented")
+	return nil, status.Errorf(codes.Unimplemented, "method Initialize not implemented")
 }
 func (UnimplementedGameLogicServer) Play2(context.Context, *RequestPlay) (*ReplyPlay, error) {
-	return nil, status.Error(codes.Unimplemented, "method Play2 not implemented")
+	return nil, status.Errorf(codes.Unimplemented, "method Play2 not implemented")
+}
+func (UnimplementedGameLogicServer) GetResult(context.Context, *RequestResult) (*ReplyPlay, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResult not implemented")
 }
 func (UnimplementedGameLogicServer) mustEmbedUnimplementedGameLogicServer() {}
 func (UnimplementedGameLogicServer) testEmbeddedByValue()                   {}
@@ -224,7 +242,7 @@ type UnsafeGameLogicServer interface {
 }
 
 func RegisterGameLogicServer(s grpc.ServiceRegistrar, srv GameLogicServer) {
-	// If the following call panics, it indicates UnimplementedGameLogicServer was
+	// If the following call pancis, it indicates UnimplementedGameLogicServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
@@ -288,6 +306,24 @@ func _GameLogic_Play2_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GameLogic_GetResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestResult)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicServer).GetResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogic_GetResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicServer).GetResult(ctx, req.(*RequestResult))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GameLogic_ServiceDesc is the grpc.ServiceDesc for GameLogic service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -307,6 +343,10 @@ var GameLogic_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "play2",
 			Handler:    _GameLogic_Play2_Handler,
 		},
+		{
+			MethodName: "getResult",
+			Handler:    _GameLogic_GetResult_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/gameLogic.proto",
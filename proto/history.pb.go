@@ -0,0 +1,437 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.4
+// source: proto/history.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RoundInfo - one settled round
+type RoundInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Table         string                 `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Round         uint64                 `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	RoundId       string                 `protobuf:"bytes,3,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	Time          int64                  `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"` // unix seconds
+	WinningNumber int32                  `protobuf:"varint,5,opt,name=winningNumber,proto3" json:"winningNumber,omitempty"`
+	Wins          []*BetWin              `protobuf:"bytes,6,rep,name=wins,proto3" json:"wins,omitempty"` // every bet and its result
+	TotalWin      int64                  `protobuf:"varint,7,opt,name=totalWin,proto3" json:"totalWin,omitempty"`
+	RandomNumbers []*RngInfo             `protobuf:"bytes,8,rep,name=randomNumbers,proto3" json:"randomNumbers,omitempty"`
+	Cheated       bool                   `protobuf:"varint,9,opt,name=cheated,proto3" json:"cheated,omitempty"`
+	Released      []*BetWin              `protobuf:"bytes,10,rep,name=released,proto3" json:"released,omitempty"` // En Prison stakes decided this round
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoundInfo) Reset() {
+	*x = RoundInfo{}
+	mi := &file_proto_history_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundInfo) ProtoMessage() {}
+
+func (x *RoundInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_history_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundInfo.ProtoReflect.Descriptor instead.
+func (*RoundInfo) Descriptor() ([]byte, []int) {
+	return file_proto_history_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RoundInfo) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *RoundInfo) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *RoundInfo) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *RoundInfo) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *RoundInfo) GetWinningNumber() int32 {
+	if x != nil {
+		return x.WinningNumber
+	}
+	return 0
+}
+
+func (x *RoundInfo) GetWins() []*BetWin {
+	if x != nil {
+		return x.Wins
+	}
+	return nil
+}
+
+func (x *RoundInfo) GetTotalWin() int64 {
+	if x != nil {
+		return x.TotalWin
+	}
+	return 0
+}
+
+func (x *RoundInfo) GetRandomNumbers() []*RngInfo {
+	if x != nil {
+		return x.RandomNumbers
+	}
+	return nil
+}
+
+func (x *RoundInfo) GetCheated() bool {
+	if x != nil {
+		return x.Cheated
+	}
+	return false
+}
+
+func (x *RoundInfo) GetReleased() []*BetWin {
+	if x != nil {
+		return x.Released
+	}
+	return nil
+}
+
+// RequestGetRound - one round by round ID, or by table and round
+type RequestGetRound struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoundId       string                 `protobuf:"bytes,1,opt,name=roundId,proto3" json:"roundId,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Round         uint64                 `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestGetRound) Reset() {
+	*x = RequestGetRound{}
+	mi := &file_proto_history_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestGetRound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestGetRound) ProtoMessage() {}
+
+func (x *RequestGetRound) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_history_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestGetRound.ProtoReflect.Descriptor instead.
+func (*RequestGetRound) Descriptor() ([]byte, []int) {
+	return file_proto_history_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RequestGetRound) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *RequestGetRound) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *RequestGetRound) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+// RequestListRounds - rounds matching every non-empty filter, newest first
+type RequestListRounds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Table         string                 `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Player        string                 `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`            // rounds with a bet by this player
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`               // unix seconds, inclusive
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                   // unix seconds, exclusive
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`             // 0 for the default
+	BeforeRound   uint64                 `protobuf:"varint,6,opt,name=beforeRound,proto3" json:"beforeRound,omitempty"` // paging: only rounds before this one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestListRounds) Reset() {
+	*x = RequestListRounds{}
+	mi := &file_proto_history_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestListRounds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestListRounds) ProtoMessage() {}
+
+func (x *RequestListRounds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_history_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestListRounds.ProtoReflect.Descriptor instead.
+func (*RequestListRounds) Descriptor() ([]byte, []int) {
+	return file_proto_history_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RequestListRounds) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *RequestListRounds) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *RequestListRounds) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RequestListRounds) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *RequestListRounds) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RequestListRounds) GetBeforeRound() uint64 {
+	if x != nil {
+		return x.BeforeRound
+	}
+	return 0
+}
+
+// ReplyListRounds - matching rounds, newest first
+type ReplyListRounds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rounds        []*RoundInfo           `protobuf:"bytes,1,rep,name=rounds,proto3" json:"rounds,omitempty"`
+	More          bool                   `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"` // more rounds match beyond limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyListRounds) Reset() {
+	*x = ReplyListRounds{}
+	mi := &file_proto_history_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyListRounds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyListRounds) ProtoMessage() {}
+
+func (x *ReplyListRounds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_history_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyListRounds.ProtoReflect.Descriptor instead.
+func (*ReplyListRounds) Descriptor() ([]byte, []int) {
+	return file_proto_history_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReplyListRounds) GetRounds() []*RoundInfo {
+	if x != nil {
+		return x.Rounds
+	}
+	return nil
+}
+
+func (x *ReplyListRounds) GetMore() bool {
+	if x != nil {
+		return x.More
+	}
+	return false
+}
+
+var File_proto_history_proto protoreflect.FileDescriptor
+
+const file_proto_history_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/history.proto\x12\x06sgc7pb\x1a\x15proto/gameLogic.proto\x1a\x14proto/roulette.proto\"\xc8\x02\n" +
+	"\tRoundInfo\x12\x14\n" +
+	"\x05table\x18\x01 \x01(\tR\x05table\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x04R\x05round\x12\x18\n" +
+	"\aroundId\x18\x03 \x01(\tR\aroundId\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\x03R\x04time\x12$\n" +
+	"\rwinningNumber\x18\x05 \x01(\x05R\rwinningNumber\x12\"\n" +
+	"\x04wins\x18\x06 \x03(\v2\x0e.sgc7pb.BetWinR\x04wins\x12\x1a\n" +
+	"\btotalWin\x18\a \x01(\x03R\btotalWin\x125\n" +
+	"\rrandomNumbers\x18\b \x03(\v2\x0f.sgc7pb.RngInfoR\rrandomNumbers\x12\x18\n" +
+	"\acheated\x18\t \x01(\bR\acheated\x12*\n" +
+	"\breleased\x18\n" +
+	" \x03(\v2\x0e.sgc7pb.BetWinR\breleased\"W\n" +
+	"\x0fRequestGetRound\x12\x18\n" +
+	"\aroundId\x18\x01 \x01(\tR\aroundId\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x14\n" +
+	"\x05round\x18\x03 \x01(\x04R\x05round\"\x9d\x01\n" +
+	"\x11RequestListRounds\x12\x14\n" +
+	"\x05table\x18\x01 \x01(\tR\x05table\x12\x16\n" +
+	"\x06player\x18\x02 \x01(\tR\x06player\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12 \n" +
+	"\vbeforeRound\x18\x06 \x01(\x04R\vbeforeRound\"P\n" +
+	"\x0fReplyListRounds\x12)\n" +
+	"\x06rounds\x18\x01 \x03(\v2\x11.sgc7pb.RoundInfoR\x06rounds\x12\x12\n" +
+	"\x04more\x18\x02 \x01(\bR\x04more2\x8c\x01\n" +
+	"\fRoundHistory\x128\n" +
+	"\bgetRound\x12\x17.sgc7pb.RequestGetRound\x1a\x11.sgc7pb.RoundInfo\"\x00\x12B\n" +
+	"\n" +
+	"listRounds\x12\x19.sgc7pb.RequestListRounds\x1a\x17.sgc7pb.ReplyListRounds\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
+
+var (
+	file_proto_history_proto_rawDescOnce sync.Once
+	file_proto_history_proto_rawDescData []byte
+)
+
+func file_proto_history_proto_rawDescGZIP() []byte {
+	file_proto_history_proto_rawDescOnce.Do(func() {
+		file_proto_history_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_history_proto_rawDesc), len(file_proto_history_proto_rawDesc)))
+	})
+	return file_proto_history_proto_rawDescData
+}
+
+var file_proto_history_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_history_proto_goTypes = []any{
+	(*RoundInfo)(nil),         // 0: sgc7pb.RoundInfo
+	(*RequestGetRound)(nil),   // 1: sgc7pb.RequestGetRound
+	(*RequestListRounds)(nil), // 2: sgc7pb.RequestListRounds
+	(*ReplyListRounds)(nil),   // 3: sgc7pb.ReplyListRounds
+	(*BetWin)(nil),            // 4: sgc7pb.BetWin
+	(*RngInfo)(nil),           // 5: sgc7pb.RngInfo
+}
+var file_proto_history_proto_depIdxs = []int32{
+	4, // 0: sgc7pb.RoundInfo.wins:type_name -> sgc7pb.BetWin
+	5, // 1: sgc7pb.RoundInfo.randomNumbers:type_name -> sgc7pb.RngInfo
+	4, // 2: sgc7pb.RoundInfo.released:type_name -> sgc7pb.BetWin
+	0, // 3: sgc7pb.ReplyListRounds.rounds:type_name -> sgc7pb.RoundInfo
+	1, // 4: sgc7pb.RoundHistory.getRound:input_type -> sgc7pb.RequestGetRound
+	2, // 5: sgc7pb.RoundHistory.listRounds:input_type -> sgc7pb.RequestListRounds
+	0, // 6: sgc7pb.RoundHistory.getRound:output_type -> sgc7pb.RoundInfo
+	3, // 7: sgc7pb.RoundHistory.listRounds:output_type -> sgc7pb.ReplyListRounds
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_history_proto_init() }
+func file_proto_history_proto_init() {
+	if File_proto_history_proto != nil {
+		return
+	}
+	file_proto_gameLogic_proto_init()
+	file_proto_roulette_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_history_proto_rawDesc), len(file_proto_history_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_history_proto_goTypes,
+		DependencyIndexes: file_proto_history_proto_depIdxs,
+		MessageInfos:      file_proto_history_proto_msgTypes,
+	}.Build()
+	File_proto_history_proto = out.File
+	file_proto_history_proto_goTypes = nil
+	file_proto_history_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.4
+// source: proto/history.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RoundHistory_GetRound_FullMethodName   = "/sgc7pb.RoundHistory/getRound"
+	RoundHistory_ListRounds_FullMethodName = "/sgc7pb.RoundHistory/listRounds"
+)
+
+// RoundHistoryClient is the client API for RoundHistory service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RoundHistory - query settled rounds
+type RoundHistoryClient interface {
+	// getRound - one round
+	GetRound(ctx context.Context, in *RequestGetRound, opts ...grpc.CallOption) (*RoundInfo, error)
+	// listRounds - rounds by table, player and time range
+	ListRounds(ctx context.Context, in *RequestListRounds, opts ...grpc.CallOption) (*ReplyListRounds, error)
+}
+
+type roundHistoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRoundHistoryClient(cc grpc.ClientConnInterface) RoundHistoryClient {
+	return &roundHistoryClient{cc}
+}
+
+func (c *roundHistoryClient) GetRound(ctx context.Context, in *RequestGetRound, opts ...grpc.CallOption) (*RoundInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoundInfo)
+	err := c.cc.Invoke(ctx, RoundHistory_GetRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roundHistoryClient) ListRounds(ctx context.Context, in *RequestListRounds, opts ...grpc.CallOption) (*ReplyListRounds, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyListRounds)
+	err := c.cc.Invoke(ctx, RoundHistory_ListRounds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RoundHistoryServer is the server API for RoundHistory service.
+// All implementations must embed UnimplementedRoundHistoryServer
+// for forward compatibility.
+//
+// RoundHistory - query settled rounds
+type RoundHistoryServer interface {
+	// getRound - one round
+	GetRound(context.Context, *RequestGetRound) (*RoundInfo, error)
+	// listRounds - rounds by table, player and time range
+	ListRounds(context.Context, *RequestListRounds) (*ReplyListRounds, error)
+	mustEmbedUnimplementedRoundHistoryServer()
+}
+
+// UnimplementedRoundHistoryServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRoundHistoryServer struct{}
+
+func (UnimplementedRoundHistoryServer) GetRound(context.Context, *RequestGetRound) (*RoundInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRound not implemented")
+}
+func (UnimplementedRoundHistoryServer) ListRounds(context.Context, *RequestListRounds) (*ReplyListRounds, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRounds not implemented")
+}
+func (UnimplementedRoundHistoryServer) mustEmbedUnimplementedRoundHistoryServer() {}
+func (UnimplementedRoundHistoryServer) testEmbeddedByValue()                      {}
+
+// UnsafeRoundHistoryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RoundHistoryServer will
+// result in compilation errors.
+type UnsafeRoundHistoryServer interface {
+	mustEmbedUnimplementedRoundHistoryServer()
+}
+
+func RegisterRoundHistoryServer(s grpc.ServiceRegistrar, srv RoundHistoryServer) {
+	// If the following call pancis, it indicates UnimplementedRoundHistoryServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RoundHistory_ServiceDesc, srv)
+}
+
+func _RoundHistory_GetRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestGetRound)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoundHistoryServer).GetRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoundHistory_GetRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoundHistoryServer).GetRound(ctx, req.(*RequestGetRound))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoundHistory_ListRounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestListRounds)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoundHistoryServer).ListRounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoundHistory_ListRounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoundHistoryServer).ListRounds(ctx, req.(*RequestListRounds))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RoundHistory_ServiceDesc is the grpc.ServiceDesc for RoundHistory service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RoundHistory_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sgc7pb.RoundHistory",
+	HandlerType: (*RoundHistoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "getRound",
+			Handler:    _RoundHistory_GetRound_Handler,
+		},
+		{
+			MethodName: "listRounds",
+			Handler:    _RoundHistory_ListRounds_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/history.proto",
+}
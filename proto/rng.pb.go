@@ -26,6 +26,9 @@ type RequestRngs struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Nums          int32                  `protobuf:"varint,1,opt,name=nums,proto3" json:"nums,omitempty"`
 	Gamecode      string                 `protobuf:"bytes,2,opt,name=gamecode,proto3" json:"gamecode,omitempty"`
+	Range         int32                  `protobuf:"varint,3,opt,name=range,proto3" json:"range,omitempty"` // 0 - full 32-bit values, >0 - unbiased values in [0, range)
+	Table         string                 `protobuf:"bytes,4,opt,name=table,proto3" json:"table,omitempty"`  // optional, every gamecode/table pair draws from its own generator
+	Chunk         int32                  `protobuf:"varint,5,opt,name=chunk,proto3" json:"chunk,omitempty"` // streamRngs only, values per streamed reply, 0 for the default
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,10 +77,34 @@ func (x *RequestRngs) GetGamecode() string {
 	return ""
 }
 
+func (x *RequestRngs) GetRange() int32 {
+	if x != nil {
+		return x.Range
+	}
+	return 0
+}
+
+func (x *RequestRngs) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *RequestRngs) GetChunk() int32 {
+	if x != nil {
+		return x.Chunk
+	}
+	return 0
+}
+
 // ReplyRngs - reply rngs
 type ReplyRngs struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Rngs          []uint32               `protobuf:"varint,1,rep,packed,name=rngs,proto3" json:"rngs,omitempty"`
+	Bits          int32                  `protobuf:"varint,2,opt,name=bits,proto3" json:"bits,omitempty"`          // bits of entropy behind every value
+	Range         int32                  `protobuf:"varint,3,opt,name=range,proto3" json:"range,omitempty"`        // 0 - full 32-bit values, >0 - values are in [0, range)
+	Algorithm     string                 `protobuf:"bytes,4,opt,name=algorithm,proto3" json:"algorithm,omitempty"` // generator behind the values: crypto, chacha20, hmac_drbg or ctr_drbg
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -119,18 +146,610 @@ func (x *ReplyRngs) GetRngs() []uint32 {
 	return nil
 }
 
+func (x *ReplyRngs) GetBits() int32 {
+	if x != nil {
+		return x.Bits
+	}
+	return 0
+}
+
+func (x *ReplyRngs) GetRange() int32 {
+	if x != nil {
+		return x.Range
+	}
+	return 0
+}
+
+func (x *ReplyRngs) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+// RequestHealth - ask for the entropy source health
+type RequestHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestHealth) Reset() {
+	*x = RequestHealth{}
+	mi := &file_proto_rng_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestHealth) ProtoMessage() {}
+
+func (x *RequestHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestHealth.ProtoReflect.Descriptor instead.
+func (*RequestHealth) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{2}
+}
+
+// ReplyHealth - SP 800-90B continuous health test status
+type ReplyHealth struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Ok               bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`                   // false once any test failed, the server then refuses to serve until an automatic retest or RngAdmin.resetHealth passes
+	Error            string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`              // first failure
+	FailedAt         int64                  `protobuf:"varint,3,opt,name=failedAt,proto3" json:"failedAt,omitempty"`       // unix seconds of the first failure
+	Samples          uint64                 `protobuf:"varint,4,opt,name=samples,proto3" json:"samples,omitempty"`         // entropy samples tested
+	RctFailures      uint64                 `protobuf:"varint,5,opt,name=rctFailures,proto3" json:"rctFailures,omitempty"` // repetition count test failures
+	AptFailures      uint64                 `protobuf:"varint,6,opt,name=aptFailures,proto3" json:"aptFailures,omitempty"` // adaptive proportion test failures
+	RctCutoff        int32                  `protobuf:"varint,7,opt,name=rctCutoff,proto3" json:"rctCutoff,omitempty"`
+	AptCutoff        int32                  `protobuf:"varint,8,opt,name=aptCutoff,proto3" json:"aptCutoff,omitempty"`
+	AptWindow        int32                  `protobuf:"varint,9,opt,name=aptWindow,proto3" json:"aptWindow,omitempty"`
+	EntropyPerSample float64                `protobuf:"fixed64,10,opt,name=entropyPerSample,proto3" json:"entropyPerSample,omitempty"`
+	Algorithm        string                 `protobuf:"bytes,11,opt,name=algorithm,proto3" json:"algorithm,omitempty"`    // generator seeded from the tested entropy source
+	Reseeds          uint64                 `protobuf:"varint,12,opt,name=reseeds,proto3" json:"reseeds,omitempty"`       // reseeds from the entropy source, 0 for crypto
+	Retests          uint64                 `protobuf:"varint,13,opt,name=retests,proto3" json:"retests,omitempty"`       // automatic start-up test reruns after failures
+	NextRetest       int64                  `protobuf:"varint,14,opt,name=nextRetest,proto3" json:"nextRetest,omitempty"` // unix seconds of the next automatic retest while failed
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReplyHealth) Reset() {
+	*x = ReplyHealth{}
+	mi := &file_proto_rng_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyHealth) ProtoMessage() {}
+
+func (x *ReplyHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyHealth.ProtoReflect.Descriptor instead.
+func (*ReplyHealth) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReplyHealth) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ReplyHealth) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReplyHealth) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetSamples() uint64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetRctFailures() uint64 {
+	if x != nil {
+		return x.RctFailures
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetAptFailures() uint64 {
+	if x != nil {
+		return x.AptFailures
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetRctCutoff() int32 {
+	if x != nil {
+		return x.RctCutoff
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetAptCutoff() int32 {
+	if x != nil {
+		return x.AptCutoff
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetAptWindow() int32 {
+	if x != nil {
+		return x.AptWindow
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetEntropyPerSample() float64 {
+	if x != nil {
+		return x.EntropyPerSample
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *ReplyHealth) GetReseeds() uint64 {
+	if x != nil {
+		return x.Reseeds
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetRetests() uint64 {
+	if x != nil {
+		return x.Retests
+	}
+	return 0
+}
+
+func (x *ReplyHealth) GetNextRetest() int64 {
+	if x != nil {
+		return x.NextRetest
+	}
+	return 0
+}
+
+// RequestResetHealth - clear a health test failure and rerun the start-up tests
+type RequestResetHealth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResetHealth) Reset() {
+	*x = RequestResetHealth{}
+	mi := &file_proto_rng_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResetHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResetHealth) ProtoMessage() {}
+
+func (x *RequestResetHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResetHealth.ProtoReflect.Descriptor instead.
+func (*RequestResetHealth) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{4}
+}
+
+// RequestStreams - ask for per-stream statistics
+type RequestStreams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gamecode      string                 `protobuf:"bytes,1,opt,name=gamecode,proto3" json:"gamecode,omitempty"` // only streams of this gamecode, empty for all
+	Counts        bool                   `protobuf:"varint,2,opt,name=counts,proto3" json:"counts,omitempty"`    // include the per-bucket counts
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestStreams) Reset() {
+	*x = RequestStreams{}
+	mi := &file_proto_rng_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestStreams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestStreams) ProtoMessage() {}
+
+func (x *RequestStreams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestStreams.ProtoReflect.Descriptor instead.
+func (*RequestStreams) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RequestStreams) GetGamecode() string {
+	if x != nil {
+		return x.Gamecode
+	}
+	return ""
+}
+
+func (x *RequestStreams) GetCounts() bool {
+	if x != nil {
+		return x.Counts
+	}
+	return false
+}
+
+// RangeStats - output statistics of one requested range
+type RangeStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Range         int32                  `protobuf:"varint,1,opt,name=range,proto3" json:"range,omitempty"` // 0 - raw 32-bit output bucketed by its top byte
+	Total         uint64                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Counts        []uint64               `protobuf:"varint,3,rep,packed,name=counts,proto3" json:"counts,omitempty"` // per bucket, only when asked for
+	ChiSquare     float64                `protobuf:"fixed64,4,opt,name=chiSquare,proto3" json:"chiSquare,omitempty"` // uniformity statistic over the buckets
+	Df            int32                  `protobuf:"varint,5,opt,name=df,proto3" json:"df,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeStats) Reset() {
+	*x = RangeStats{}
+	mi := &file_proto_rng_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeStats) ProtoMessage() {}
+
+func (x *RangeStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeStats.ProtoReflect.Descriptor instead.
+func (*RangeStats) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RangeStats) GetRange() int32 {
+	if x != nil {
+		return x.Range
+	}
+	return 0
+}
+
+func (x *RangeStats) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *RangeStats) GetCounts() []uint64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *RangeStats) GetChiSquare() float64 {
+	if x != nil {
+		return x.ChiSquare
+	}
+	return 0
+}
+
+func (x *RangeStats) GetDf() int32 {
+	if x != nil {
+		return x.Df
+	}
+	return 0
+}
+
+// StreamStats - one independent generator instance
+type StreamStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gamecode      string                 `protobuf:"bytes,1,opt,name=gamecode,proto3" json:"gamecode,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Requests      uint64                 `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
+	Values        uint64                 `protobuf:"varint,5,opt,name=values,proto3" json:"values,omitempty"`
+	Rejected      uint64                 `protobuf:"varint,6,opt,name=rejected,proto3" json:"rejected,omitempty"` // raw values rejected while scaling
+	Reseeds       uint64                 `protobuf:"varint,7,opt,name=reseeds,proto3" json:"reseeds,omitempty"`
+	Created       int64                  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`   // unix seconds
+	LastUsed      int64                  `protobuf:"varint,9,opt,name=lastUsed,proto3" json:"lastUsed,omitempty"` // unix seconds
+	Ranges        []*RangeStats          `protobuf:"bytes,10,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStats) Reset() {
+	*x = StreamStats{}
+	mi := &file_proto_rng_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStats) ProtoMessage() {}
+
+func (x *StreamStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStats.ProtoReflect.Descriptor instead.
+func (*StreamStats) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamStats) GetGamecode() string {
+	if x != nil {
+		return x.Gamecode
+	}
+	return ""
+}
+
+func (x *StreamStats) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *StreamStats) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *StreamStats) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *StreamStats) GetValues() uint64 {
+	if x != nil {
+		return x.Values
+	}
+	return 0
+}
+
+func (x *StreamStats) GetRejected() uint64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *StreamStats) GetReseeds() uint64 {
+	if x != nil {
+		return x.Reseeds
+	}
+	return 0
+}
+
+func (x *StreamStats) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *StreamStats) GetLastUsed() int64 {
+	if x != nil {
+		return x.LastUsed
+	}
+	return 0
+}
+
+func (x *StreamStats) GetRanges() []*RangeStats {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+// ReplyStreams - per-stream statistics
+type ReplyStreams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Streams       []*StreamStats         `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyStreams) Reset() {
+	*x = ReplyStreams{}
+	mi := &file_proto_rng_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyStreams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyStreams) ProtoMessage() {}
+
+func (x *ReplyStreams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rng_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyStreams.ProtoReflect.Descriptor instead.
+func (*ReplyStreams) Descriptor() ([]byte, []int) {
+	return file_proto_rng_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReplyStreams) GetStreams() []*StreamStats {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
 var File_proto_rng_proto protoreflect.FileDescriptor
 
 const file_proto_rng_proto_rawDesc = "" +
 	"\n" +
-	"\x0fproto/rng.proto\x12\x06sgc7pb\"=\n" +
+	"\x0fproto/rng.proto\x12\x06sgc7pb\"\x7f\n" +
 	"\vRequestRngs\x12\x12\n" +
 	"\x04nums\x18\x01 \x01(\x05R\x04nums\x12\x1a\n" +
-	"\bgamecode\x18\x02 \x01(\tR\bgamecode\"\x1f\n" +
+	"\bgamecode\x18\x02 \x01(\tR\bgamecode\x12\x14\n" +
+	"\x05range\x18\x03 \x01(\x05R\x05range\x12\x14\n" +
+	"\x05table\x18\x04 \x01(\tR\x05table\x12\x14\n" +
+	"\x05chunk\x18\x05 \x01(\x05R\x05chunk\"g\n" +
 	"\tReplyRngs\x12\x12\n" +
-	"\x04rngs\x18\x01 \x03(\rR\x04rngs2:\n" +
+	"\x04rngs\x18\x01 \x03(\rR\x04rngs\x12\x12\n" +
+	"\x04bits\x18\x02 \x01(\x05R\x04bits\x12\x14\n" +
+	"\x05range\x18\x03 \x01(\x05R\x05range\x12\x1c\n" +
+	"\talgorithm\x18\x04 \x01(\tR\talgorithm\"\x0f\n" +
+	"\rRequestHealth\"\xa5\x03\n" +
+	"\vReplyHealth\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1a\n" +
+	"\bfailedAt\x18\x03 \x01(\x03R\bfailedAt\x12\x18\n" +
+	"\asamples\x18\x04 \x01(\x04R\asamples\x12 \n" +
+	"\vrctFailures\x18\x05 \x01(\x04R\vrctFailures\x12 \n" +
+	"\vaptFailures\x18\x06 \x01(\x04R\vaptFailures\x12\x1c\n" +
+	"\trctCutoff\x18\a \x01(\x05R\trctCutoff\x12\x1c\n" +
+	"\taptCutoff\x18\b \x01(\x05R\taptCutoff\x12\x1c\n" +
+	"\taptWindow\x18\t \x01(\x05R\taptWindow\x12*\n" +
+	"\x10entropyPerSample\x18\n" +
+	" \x01(\x01R\x10entropyPerSample\x12\x1c\n" +
+	"\talgorithm\x18\v \x01(\tR\talgorithm\x12\x18\n" +
+	"\areseeds\x18\f \x01(\x04R\areseeds\x12\x18\n" +
+	"\aretests\x18\r \x01(\x04R\aretests\x12\x1e\n" +
+	"\n" +
+	"nextRetest\x18\x0e \x01(\x03R\n" +
+	"nextRetest\"\x14\n" +
+	"\x12RequestResetHealth\"D\n" +
+	"\x0eRequestStreams\x12\x1a\n" +
+	"\bgamecode\x18\x01 \x01(\tR\bgamecode\x12\x16\n" +
+	"\x06counts\x18\x02 \x01(\bR\x06counts\"~\n" +
+	"\n" +
+	"RangeStats\x12\x14\n" +
+	"\x05range\x18\x01 \x01(\x05R\x05range\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x12\x16\n" +
+	"\x06counts\x18\x03 \x03(\x04R\x06counts\x12\x1c\n" +
+	"\tchiSquare\x18\x04 \x01(\x01R\tchiSquare\x12\x0e\n" +
+	"\x02df\x18\x05 \x01(\x05R\x02df\"\xa9\x02\n" +
+	"\vStreamStats\x12\x1a\n" +
+	"\bgamecode\x18\x01 \x01(\tR\bgamecode\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm\x12\x1a\n" +
+	"\brequests\x18\x04 \x01(\x04R\brequests\x12\x16\n" +
+	"\x06values\x18\x05 \x01(\x04R\x06values\x12\x1a\n" +
+	"\brejected\x18\x06 \x01(\x04R\brejected\x12\x18\n" +
+	"\areseeds\x18\a \x01(\x04R\areseeds\x12\x18\n" +
+	"\acreated\x18\b \x01(\x03R\acreated\x12\x1a\n" +
+	"\blastUsed\x18\t \x01(\x03R\blastUsed\x12*\n" +
+	"\x06ranges\x18\n" +
+	" \x03(\v2\x12.sgc7pb.RangeStatsR\x06ranges\"=\n" +
+	"\fReplyStreams\x12-\n" +
+	"\astreams\x18\x01 \x03(\v2\x13.sgc7pb.StreamStatsR\astreams2\xac\x01\n" +
 	"\x03Rng\x123\n" +
-	"\agetRngs\x12\x13.sgc7pb.RequestRngs\x1a\x11.sgc7pb.ReplyRngs\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
+	"\agetRngs\x12\x13.sgc7pb.RequestRngs\x1a\x11.sgc7pb.ReplyRngs\"\x00\x128\n" +
+	"\n" +
+	"streamRngs\x12\x13.sgc7pb.RequestRngs\x1a\x11.sgc7pb.ReplyRngs\"\x000\x01\x126\n" +
+	"\x06health\x12\x15.sgc7pb.RequestHealth\x1a\x13.sgc7pb.ReplyHealth\"\x002\x87\x01\n" +
+	"\bRngAdmin\x129\n" +
+	"\astreams\x12\x16.sgc7pb.RequestStreams\x1a\x14.sgc7pb.ReplyStreams\"\x00\x12@\n" +
+	"\vresetHealth\x12\x1a.sgc7pb.RequestResetHealth\x1a\x13.sgc7pb.ReplyHealth\"\x00B'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
 
 var (
 	file_proto_rng_proto_rawDescOnce sync.Once
@@ -144,19 +763,36 @@ func file_proto_rng_proto_rawDescGZIP() []byte {
 	return file_proto_rng_proto_rawDescData
 }
 
-var file_proto_rng_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_rng_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_proto_rng_proto_goTypes = []any{
-	(*RequestRngs)(nil), // 0: sgc7pb.RequestRngs
-	(*ReplyRngs)(nil),   // 1: sgc7pb.ReplyRngs
+	(*RequestRngs)(nil),        // 0: sgc7pb.RequestRngs
+	(*ReplyRngs)(nil),          // 1: sgc7pb.ReplyRngs
+	(*RequestHealth)(nil),      // 2: sgc7pb.RequestHealth
+	(*ReplyHealth)(nil),        // 3: sgc7pb.ReplyHealth
+	(*RequestResetHealth)(nil), // 4: sgc7pb.RequestResetHealth
+	(*RequestStreams)(nil),     // 5: sgc7pb.RequestStreams
+	(*RangeStats)(nil),         // 6: sgc7pb.RangeStats
+	(*StreamStats)(nil),        // 7: sgc7pb.StreamStats
+	(*ReplyStreams)(nil),       // 8: sgc7pb.ReplyStreams
 }
 var file_proto_rng_proto_depIdxs = []int32{
-	0, // 0: sgc7pb.Rng.getRngs:input_type -> sgc7pb.RequestRngs
-	1, // 1: sgc7pb.Rng.getRngs:output_type -> sgc7pb.ReplyRngs
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6, // 0: sgc7pb.StreamStats.ranges:type_name -> sgc7pb.RangeStats
+	7, // 1: sgc7pb.ReplyStreams.streams:type_name -> sgc7pb.StreamStats
+	0, // 2: sgc7pb.Rng.getRngs:input_type -> sgc7pb.RequestRngs
+	0, // 3: sgc7pb.Rng.streamRngs:input_type -> sgc7pb.RequestRngs
+	2, // 4: sgc7pb.Rng.health:input_type -> sgc7pb.RequestHealth
+	5, // 5: sgc7pb.RngAdmin.streams:input_type -> sgc7pb.RequestStreams
+	4, // 6: sgc7pb.RngAdmin.resetHealth:input_type -> sgc7pb.RequestResetHealth
+	1, // 7: sgc7pb.Rng.getRngs:output_type -> sgc7pb.ReplyRngs
+	1, // 8: sgc7pb.Rng.streamRngs:output_type -> sgc7pb.ReplyRngs
+	3, // 9: sgc7pb.Rng.health:output_type -> sgc7pb.ReplyHealth
+	8, // 10: sgc7pb.RngAdmin.streams:output_type -> sgc7pb.ReplyStreams
+	3, // 11: sgc7pb.RngAdmin.resetHealth:output_type -> sgc7pb.ReplyHealth
+	7, // [7:12] is the sub-list for method output_type
+	2, // [2:7] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_rng_proto_init() }
@@ -170,9 +806,9 @@ func file_proto_rng_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_rng_proto_rawDesc), len(file_proto_rng_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   9,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_rng_proto_goTypes,
 		DependencyIndexes: file_proto_rng_proto_depIdxs,
@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Rng_GetRngs_FullMethodName = "/sgc7pb.Rng/getRngs"
+	Rng_GetRngs_FullMethodName    = "/sgc7pb.Rng/getRngs"
+	Rng_StreamRngs_FullMethodName = "/sgc7pb.Rng/streamRngs"
+	Rng_Health_FullMethodName     = "/sgc7pb.Rng/health"
 )
 
 // RngClient is the client API for Rng service.
@@ -30,6 +32,10 @@ const (
 type RngClient interface {
 	// getRngs - get rngs
 	GetRngs(ctx context.Context, in *RequestRngs, opts ...grpc.CallOption) (*ReplyRngs, error)
+	// streamRngs - stream nums rngs in chunks, for bulk consumers
+	StreamRngs(ctx context.Context, in *RequestRngs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplyRngs], error)
+	// health - entropy source health
+	Health(ctx context.Context, in *RequestHealth, opts ...grpc.CallOption) (*ReplyHealth, error)
 }
 
 type rngClient struct {
@@ -50,6 +56,35 @@ func (c *rngClient) GetRngs(ctx context.Context, in *RequestRngs, opts ...grpc.C
 	return out, nil
 }
 
+func (c *rngClient) StreamRngs(ctx context.Context, in *RequestRngs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplyRngs], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Rng_ServiceDesc.Streams[0], Rng_StreamRngs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RequestRngs, ReplyRngs]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rng_StreamRngsClient = grpc.ServerStreamingClient[ReplyRngs]
+
+func (c *rngClient) Health(ctx context.Context, in *RequestHealth, opts ...grpc.CallOption) (*ReplyHealth, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyHealth)
+	err := c.cc.Invoke(ctx, Rng_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RngServer is the server API for Rng service.
 // All implementations must embed UnimplementedRngServer
 // for forward compatibility.
@@ -58,6 +93,10 @@ func (c *rngClient) GetRngs(ctx context.Context, in *RequestRngs, opts ...grpc.C
 type RngServer interface {
 	// getRngs - get rngs
 	GetRngs(context.Context, *RequestRngs) (*ReplyRngs, error)
+	// streamRngs - stream nums rngs in chunks, for bulk consumers
+	StreamRngs(*RequestRngs, grpc.ServerStreamingServer[ReplyRngs]) error
+	// health - entropy source health
+	Health(context.Context, *RequestHealth) (*ReplyHealth, error)
 	mustEmbedUnimplementedRngServer()
 }
 
@@ -71,6 +110,12 @@ type UnimplementedRngServer struct{}
 func (UnimplementedRngServer) GetRngs(context.Context, *RequestRngs) (*ReplyRngs, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRngs not implemented")
 }
+func (UnimplementedRngServer) StreamRngs(*RequestRngs, grpc.ServerStreamingServer[ReplyRngs]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRngs not implemented")
+}
+func (UnimplementedRngServer) Health(context.Context, *RequestHealth) (*ReplyHealth, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
 func (UnimplementedRngServer) mustEmbedUnimplementedRngServer() {}
 func (UnimplementedRngServer) testEmbeddedByValue()             {}
 
@@ -110,6 +155,35 @@ func _Rng_GetRngs_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Rng_StreamRngs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RequestRngs)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RngServer).StreamRngs(m, &grpc.GenericServerStream[RequestRngs, ReplyRngs]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rng_StreamRngsServer = grpc.ServerStreamingServer[ReplyRngs]
+
+func _Rng_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestHealth)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RngServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rng_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RngServer).Health(ctx, req.(*RequestHealth))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Rng_ServiceDesc is the grpc.ServiceDesc for Rng service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,6 +195,164 @@ var Rng_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "getRngs",
 			Handler:    _Rng_GetRngs_Handler,
 		},
+		{
+			MethodName: "health",
+			Handler:    _Rng_Health_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "streamRngs",
+			Handler:       _Rng_StreamRngs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/rng.proto",
+}
+
+const (
+	RngAdmin_Streams_FullMethodName     = "/sgc7pb.RngAdmin/streams"
+	RngAdmin_ResetHealth_FullMethodName = "/sgc7pb.RngAdmin/resetHealth"
+)
+
+// RngAdminClient is the client API for RngAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RngAdmin - RNG administration, not meant to be exposed to game servers
+type RngAdminClient interface {
+	// streams - per gamecode/table stream counters and output statistics
+	Streams(ctx context.Context, in *RequestStreams, opts ...grpc.CallOption) (*ReplyStreams, error)
+	// resetHealth - after the entropy source was fixed, rerun the start-up health tests and serve again if they pass
+	ResetHealth(ctx context.Context, in *RequestResetHealth, opts ...grpc.CallOption) (*ReplyHealth, error)
+}
+
+type rngAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRngAdminClient(cc grpc.ClientConnInterface) RngAdminClient {
+	return &rngAdminClient{cc}
+}
+
+func (c *rngAdminClient) Streams(ctx context.Context, in *RequestStreams, opts ...grpc.CallOption) (*ReplyStreams, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyStreams)
+	err := c.cc.Invoke(ctx, RngAdmin_Streams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rngAdminClient) ResetHealth(ctx context.Context, in *RequestResetHealth, opts ...grpc.CallOption) (*ReplyHealth, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyHealth)
+	err := c.cc.Invoke(ctx, RngAdmin_ResetHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RngAdminServer is the server API for RngAdmin service.
+// All implementations must embed UnimplementedRngAdminServer
+// for forward compatibility.
+//
+// RngAdmin - RNG administration, not meant to be exposed to game servers
+type RngAdminServer interface {
+	// streams - per gamecode/table stream counters and output statistics
+	Streams(context.Context, *RequestStreams) (*ReplyStreams, error)
+	// resetHealth - after the entropy source was fixed, rerun the start-up health tests and serve again if they pass
+	ResetHealth(context.Context, *RequestResetHealth) (*ReplyHealth, error)
+	mustEmbedUnimplementedRngAdminServer()
+}
+
+// UnimplementedRngAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRngAdminServer struct{}
+
+func (UnimplementedRngAdminServer) Streams(context.Context, *RequestStreams) (*ReplyStreams, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Streams not implemented")
+}
+func (UnimplementedRngAdminServer) ResetHealth(context.Context, *RequestResetHealth) (*ReplyHealth, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetHealth not implemented")
+}
+func (UnimplementedRngAdminServer) mustEmbedUnimplementedRngAdminServer() {}
+func (UnimplementedRngAdminServer) testEmbeddedByValue()                  {}
+
+// UnsafeRngAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RngAdminServer will
+// result in compilation errors.
+type UnsafeRngAdminServer interface {
+	mustEmbedUnimplementedRngAdminServer()
+}
+
+func RegisterRngAdminServer(s grpc.ServiceRegistrar, srv RngAdminServer) {
+	// If the following call pancis, it indicates UnimplementedRngAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RngAdmin_ServiceDesc, srv)
+}
+
+func _RngAdmin_Streams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestStreams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RngAdminServer).Streams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RngAdmin_Streams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RngAdminServer).Streams(ctx, req.(*RequestStreams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RngAdmin_ResetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestResetHealth)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RngAdminServer).ResetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RngAdmin_ResetHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RngAdminServer).ResetHealth(ctx, req.(*RequestResetHealth))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RngAdmin_ServiceDesc is the grpc.ServiceDesc for RngAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RngAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sgc7pb.RngAdmin",
+	HandlerType: (*RngAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "streams",
+			Handler:    _RngAdmin_Streams_Handler,
+		},
+		{
+			MethodName: "resetHealth",
+			Handler:    _RngAdmin_ResetHealth_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/rng.proto",
@@ -25,6 +25,7 @@ type Bet struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Numbers       []int32                `protobuf:"varint,1,rep,packed,name=numbers,proto3" json:"numbers,omitempty"` // 下注的数字
 	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`          // 下注金额
+	Player        string                 `protobuf:"bytes,3,opt,name=player,proto3" json:"player,omitempty"`           // 下注的玩家, 用于按玩家查询历史, 可选
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +74,13 @@ func (x *Bet) GetAmount() int64 {
 	return 0
 }
 
+func (x *Bet) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
 // 单个下注的输赢
 type BetWin struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -150,19 +158,126 @@ func (x *BetWin) GetPayout() int32 {
 	return 0
 }
 
+// 轮盘动画参数, 由结果和RNG确定, 所有客户端和视频渲染一致
+type WheelAnimation struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PocketIndex     int32                  `protobuf:"varint,1,opt,name=pocketIndex,proto3" json:"pocketIndex,omitempty"`          // 获胜数字在轮盘上的位置(wheelNumbers 下标)
+	RotorStartAngle float64                `protobuf:"fixed64,2,opt,name=rotorStartAngle,proto3" json:"rotorStartAngle,omitempty"` // 转子初始角度, 度
+	RotorSpeed      float64                `protobuf:"fixed64,3,opt,name=rotorSpeed,proto3" json:"rotorSpeed,omitempty"`           // 转子角速度, 度/秒, 顺时针
+	BallStartAngle  float64                `protobuf:"fixed64,4,opt,name=ballStartAngle,proto3" json:"ballStartAngle,omitempty"`   // 球初始角度, 度
+	BallRevolutions int32                  `protobuf:"varint,5,opt,name=ballRevolutions,proto3" json:"ballRevolutions,omitempty"`  // 球逆时针转动的整圈数
+	Duration        float64                `protobuf:"fixed64,6,opt,name=duration,proto3" json:"duration,omitempty"`               // 从发球到落定的时间, 秒
+	PocketAngle     float64                `protobuf:"fixed64,7,opt,name=pocketAngle,proto3" json:"pocketAngle,omitempty"`         // 获胜格在转子上的角度, 度
+	LandingAngle    float64                `protobuf:"fixed64,8,opt,name=landingAngle,proto3" json:"landingAngle,omitempty"`       // 落定时获胜格(即球)在台面上的角度, 度
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WheelAnimation) Reset() {
+	*x = WheelAnimation{}
+	mi := &file_proto_roulette_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WheelAnimation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WheelAnimation) ProtoMessage() {}
+
+func (x *WheelAnimation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_roulette_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WheelAnimation.ProtoReflect.Descriptor instead.
+func (*WheelAnimation) Descriptor() ([]byte, []int) {
+	return file_proto_roulette_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WheelAnimation) GetPocketIndex() int32 {
+	if x != nil {
+		return x.PocketIndex
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetRotorStartAngle() float64 {
+	if x != nil {
+		return x.RotorStartAngle
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetRotorSpeed() float64 {
+	if x != nil {
+		return x.RotorSpeed
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetBallStartAngle() float64 {
+	if x != nil {
+		return x.BallStartAngle
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetBallRevolutions() int32 {
+	if x != nil {
+		return x.BallRevolutions
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetPocketAngle() float64 {
+	if x != nil {
+		return x.PocketAngle
+	}
+	return 0
+}
+
+func (x *WheelAnimation) GetLandingAngle() float64 {
+	if x != nil {
+		return x.LandingAngle
+	}
+	return 0
+}
+
 // GameModParam
 type GameModParam struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WinningNumber int32                  `protobuf:"varint,1,opt,name=winningNumber,proto3" json:"winningNumber,omitempty"`
 	Wins          []*BetWin              `protobuf:"bytes,2,rep,name=wins,proto3" json:"wins,omitempty"`
 	TotalWin      int64                  `protobuf:"varint,3,opt,name=totalWin,proto3" json:"totalWin,omitempty"`
+	Wheel         *WheelAnimation        `protobuf:"bytes,4,opt,name=wheel,proto3" json:"wheel,omitempty"`
+	Fair          *FairProof             `protobuf:"bytes,5,opt,name=fair,proto3" json:"fair,omitempty"`             // 仅在 provably fair 模式下存在
+	Cheated       bool                   `protobuf:"varint,6,opt,name=cheated,proto3" json:"cheated,omitempty"`      // 获胜数字由作弊指令强制, 不是随机结果
+	Cheat         string                 `protobuf:"bytes,7,opt,name=cheat,proto3" json:"cheat,omitempty"`           // 本局生效的作弊, 如 pocket:17
+	Imprisoned    []*Bet                 `protobuf:"bytes,8,rep,name=imprisoned,proto3" json:"imprisoned,omitempty"` // En Prison: 本局开 0 时扣押的平注, 由下一局决定
+	Released      []*BetWin              `protobuf:"bytes,9,rep,name=released,proto3" json:"released,omitempty"`     // 上一局扣押的平注在本局的结果, 赢时退回本金
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GameModParam) Reset() {
 	*x = GameModParam{}
-	mi := &file_proto_roulette_proto_msgTypes[2]
+	mi := &file_proto_roulette_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -174,7 +289,7 @@ func (x *GameModParam) String() string {
 func (*GameModParam) ProtoMessage() {}
 
 func (x *GameModParam) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_roulette_proto_msgTypes[2]
+	mi := &file_proto_roulette_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -187,7 +302,7 @@ func (x *GameModParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameModParam.ProtoReflect.Descriptor instead.
 func (*GameModParam) Descriptor() ([]byte, []int) {
-	return file_proto_roulette_proto_rawDescGZIP(), []int{2}
+	return file_proto_roulette_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GameModParam) GetWinningNumber() int32 {
@@ -211,17 +326,60 @@ func (x *GameModParam) GetTotalWin() int64 {
 	return 0
 }
 
+func (x *GameModParam) GetWheel() *WheelAnimation {
+	if x != nil {
+		return x.Wheel
+	}
+	return nil
+}
+
+func (x *GameModParam) GetFair() *FairProof {
+	if x != nil {
+		return x.Fair
+	}
+	return nil
+}
+
+func (x *GameModParam) GetCheated() bool {
+	if x != nil {
+		return x.Cheated
+	}
+	return false
+}
+
+func (x *GameModParam) GetCheat() string {
+	if x != nil {
+		return x.Cheat
+	}
+	return ""
+}
+
+func (x *GameModParam) GetImprisoned() []*Bet {
+	if x != nil {
+		return x.Imprisoned
+	}
+	return nil
+}
+
+func (x *GameModParam) GetReleased() []*BetWin {
+	if x != nil {
+		return x.Released
+	}
+	return nil
+}
+
 // 下注请求
 type BetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Bets          []*Bet                 `protobuf:"bytes,1,rep,name=bets,proto3" json:"bets,omitempty"`
+	ClientSeed    string                 `protobuf:"bytes,2,opt,name=clientSeed,proto3" json:"clientSeed,omitempty"` // provably fair 模式下玩家提供的种子
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BetRequest) Reset() {
 	*x = BetRequest{}
-	mi := &file_proto_roulette_proto_msgTypes[3]
+	mi := &file_proto_roulette_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -233,7 +391,7 @@ func (x *BetRequest) String() string {
 func (*BetRequest) ProtoMessage() {}
 
 func (x *BetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_roulette_proto_msgTypes[3]
+	mi := &file_proto_roulette_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,7 +404,7 @@ func (x *BetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BetRequest.ProtoReflect.Descriptor instead.
 func (*BetRequest) Descriptor() ([]byte, []int) {
-	return file_proto_roulette_proto_rawDescGZIP(), []int{3}
+	return file_proto_roulette_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *BetRequest) GetBets() []*Bet {
@@ -256,27 +414,281 @@ func (x *BetRequest) GetBets() []*Bet {
 	return nil
 }
 
+func (x *BetRequest) GetClientSeed() string {
+	if x != nil {
+		return x.ClientSeed
+	}
+	return ""
+}
+
+// 常用的下注组合
+type BetLayout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bets          []*Bet                 `protobuf:"bytes,1,rep,name=bets,proto3" json:"bets,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // 使用次数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BetLayout) Reset() {
+	*x = BetLayout{}
+	mi := &file_proto_roulette_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BetLayout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BetLayout) ProtoMessage() {}
+
+func (x *BetLayout) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_roulette_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BetLayout.ProtoReflect.Descriptor instead.
+func (*BetLayout) Descriptor() ([]byte, []int) {
+	return file_proto_roulette_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BetLayout) GetBets() []*Bet {
+	if x != nil {
+		return x.Bets
+	}
+	return nil
+}
+
+func (x *BetLayout) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 玩家公开状态, 放在 PlayerState.public 中
+type RoulettePublicState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastResults   []int32                `protobuf:"varint,1,rep,packed,name=lastResults,proto3" json:"lastResults,omitempty"` // 最近的获胜数字, 新的在前
+	Favourites    []*BetLayout           `protobuf:"bytes,2,rep,name=favourites,proto3" json:"favourites,omitempty"`           // 最常用的下注组合, 按使用次数排列
+	LastBets      []*Bet                 `protobuf:"bytes,3,rep,name=lastBets,proto3" json:"lastBets,omitempty"`               // 上一局的下注, 用于重复下注
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoulettePublicState) Reset() {
+	*x = RoulettePublicState{}
+	mi := &file_proto_roulette_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoulettePublicState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoulettePublicState) ProtoMessage() {}
+
+func (x *RoulettePublicState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_roulette_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoulettePublicState.ProtoReflect.Descriptor instead.
+func (*RoulettePublicState) Descriptor() ([]byte, []int) {
+	return file_proto_roulette_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RoulettePublicState) GetLastResults() []int32 {
+	if x != nil {
+		return x.LastResults
+	}
+	return nil
+}
+
+func (x *RoulettePublicState) GetFavourites() []*BetLayout {
+	if x != nil {
+		return x.Favourites
+	}
+	return nil
+}
+
+func (x *RoulettePublicState) GetLastBets() []*Bet {
+	if x != nil {
+		return x.LastBets
+	}
+	return nil
+}
+
+// 玩家私有状态, 放在 PlayerState.private 中, 由服务端签名
+type RoulettePrivateState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnPrison      []*Bet                 `protobuf:"bytes,1,rep,name=enPrison,proto3" json:"enPrison,omitempty"`  // 扣押中的平注
+	Rounds        uint64                 `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`     // 本次会话的局数
+	TotalBet      int64                  `protobuf:"varint,3,opt,name=totalBet,proto3" json:"totalBet,omitempty"` // 本次会话的下注总额
+	TotalWin      int64                  `protobuf:"varint,4,opt,name=totalWin,proto3" json:"totalWin,omitempty"` // 本次会话的赢分总额, 含退回的本金
+	Mac           []byte                 `protobuf:"bytes,5,opt,name=mac,proto3" json:"mac,omitempty"`            // 服务端密钥对其余字段的 HMAC-SHA256, 防止篡改
+	Session       string                 `protobuf:"bytes,6,opt,name=session,proto3" json:"session,omitempty"`    // 会话ID, 扣押中的平注由服务端按会话跟踪
+	Round         uint64                 `protobuf:"varint,7,opt,name=round,proto3" json:"round,omitempty"`       // 签发该状态的局号, 持有扣押平注的会话只接受最新的状态
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoulettePrivateState) Reset() {
+	*x = RoulettePrivateState{}
+	mi := &file_proto_roulette_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoulettePrivateState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoulettePrivateState) ProtoMessage() {}
+
+func (x *RoulettePrivateState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_roulette_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoulettePrivateState.ProtoReflect.Descriptor instead.
+func (*RoulettePrivateState) Descriptor() ([]byte, []int) {
+	return file_proto_roulette_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RoulettePrivateState) GetEnPrison() []*Bet {
+	if x != nil {
+		return x.EnPrison
+	}
+	return nil
+}
+
+func (x *RoulettePrivateState) GetRounds() uint64 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *RoulettePrivateState) GetTotalBet() int64 {
+	if x != nil {
+		return x.TotalBet
+	}
+	return 0
+}
+
+func (x *RoulettePrivateState) GetTotalWin() int64 {
+	if x != nil {
+		return x.TotalWin
+	}
+	return 0
+}
+
+func (x *RoulettePrivateState) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+func (x *RoulettePrivateState) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *RoulettePrivateState) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
 var File_proto_roulette_proto protoreflect.FileDescriptor
 
 const file_proto_roulette_proto_rawDesc = "" +
 	"\n" +
-	"\x14proto/roulette.proto\x12\x06sgc7pb\"7\n" +
+	"\x14proto/roulette.proto\x12\x06sgc7pb\x1a\x10proto/fair.proto\"O\n" +
 	"\x03Bet\x12\x18\n" +
 	"\anumbers\x18\x01 \x03(\x05R\anumbers\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\x89\x01\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06player\x18\x03 \x01(\tR\x06player\"\x89\x01\n" +
 	"\x06BetWin\x12\x1d\n" +
 	"\x03bet\x18\x01 \x01(\v2\v.sgc7pb.BetR\x03bet\x12\x18\n" +
 	"\abetType\x18\x02 \x01(\tR\abetType\x12\x10\n" +
 	"\x03win\x18\x03 \x01(\bR\x03win\x12\x1c\n" +
 	"\twinAmount\x18\x04 \x01(\x03R\twinAmount\x12\x16\n" +
-	"\x06payout\x18\x05 \x01(\x05R\x06payout\"t\n" +
+	"\x06payout\x18\x05 \x01(\x05R\x06payout\"\xb0\x02\n" +
+	"\x0eWheelAnimation\x12 \n" +
+	"\vpocketIndex\x18\x01 \x01(\x05R\vpocketIndex\x12(\n" +
+	"\x0frotorStartAngle\x18\x02 \x01(\x01R\x0frotorStartAngle\x12\x1e\n" +
+	"\n" +
+	"rotorSpeed\x18\x03 \x01(\x01R\n" +
+	"rotorSpeed\x12&\n" +
+	"\x0eballStartAngle\x18\x04 \x01(\x01R\x0eballStartAngle\x12(\n" +
+	"\x0fballRevolutions\x18\x05 \x01(\x05R\x0fballRevolutions\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\x01R\bduration\x12 \n" +
+	"\vpocketAngle\x18\a \x01(\x01R\vpocketAngle\x12\"\n" +
+	"\flandingAngle\x18\b \x01(\x01R\flandingAngle\"\xd2\x02\n" +
 	"\fGameModParam\x12$\n" +
 	"\rwinningNumber\x18\x01 \x01(\x05R\rwinningNumber\x12\"\n" +
 	"\x04wins\x18\x02 \x03(\v2\x0e.sgc7pb.BetWinR\x04wins\x12\x1a\n" +
-	"\btotalWin\x18\x03 \x01(\x03R\btotalWin\"-\n" +
+	"\btotalWin\x18\x03 \x01(\x03R\btotalWin\x12,\n" +
+	"\x05wheel\x18\x04 \x01(\v2\x16.sgc7pb.WheelAnimationR\x05wheel\x12%\n" +
+	"\x04fair\x18\x05 \x01(\v2\x11.sgc7pb.FairProofR\x04fair\x12\x18\n" +
+	"\acheated\x18\x06 \x01(\bR\acheated\x12\x14\n" +
+	"\x05cheat\x18\a \x01(\tR\x05cheat\x12+\n" +
+	"\n" +
+	"imprisoned\x18\b \x03(\v2\v.sgc7pb.BetR\n" +
+	"imprisoned\x12*\n" +
+	"\breleased\x18\t \x03(\v2\x0e.sgc7pb.BetWinR\breleased\"M\n" +
 	"\n" +
 	"BetRequest\x12\x1f\n" +
-	"\x04bets\x18\x01 \x03(\v2\v.sgc7pb.BetR\x04betsB'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
+	"\x04bets\x18\x01 \x03(\v2\v.sgc7pb.BetR\x04bets\x12\x1e\n" +
+	"\n" +
+	"clientSeed\x18\x02 \x01(\tR\n" +
+	"clientSeed\"B\n" +
+	"\tBetLayout\x12\x1f\n" +
+	"\x04bets\x18\x01 \x03(\v2\v.sgc7pb.BetR\x04bets\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\x93\x01\n" +
+	"\x13RoulettePublicState\x12 \n" +
+	"\vlastResults\x18\x01 \x03(\x05R\vlastResults\x121\n" +
+	"\n" +
+	"favourites\x18\x02 \x03(\v2\x11.sgc7pb.BetLayoutR\n" +
+	"favourites\x12'\n" +
+	"\blastBets\x18\x03 \x03(\v2\v.sgc7pb.BetR\blastBets\"\xd1\x01\n" +
+	"\x14RoulettePrivateState\x12'\n" +
+	"\benPrison\x18\x01 \x03(\v2\v.sgc7pb.BetR\benPrison\x12\x16\n" +
+	"\x06rounds\x18\x02 \x01(\x04R\x06rounds\x12\x1a\n" +
+	"\btotalBet\x18\x03 \x01(\x03R\btotalBet\x12\x1a\n" +
+	"\btotalWin\x18\x04 \x01(\x03R\btotalWin\x12\x10\n" +
+	"\x03mac\x18\x05 \x01(\fR\x03mac\x12\x18\n" +
+	"\asession\x18\x06 \x01(\tR\asession\x12\x14\n" +
+	"\x05round\x18\a \x01(\x04R\x05roundB'Z%gitee.com/heartfun/rouletteserv/protob\x06proto3"
 
 var (
 	file_proto_roulette_proto_rawDescOnce sync.Once
@@ -290,22 +702,35 @@ func file_proto_roulette_proto_rawDescGZIP() []byte {
 	return file_proto_roulette_proto_rawDescData
 }
 
-var file_proto_roulette_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_roulette_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_proto_roulette_proto_goTypes = []any{
-	(*Bet)(nil),          // 0: sgc7pb.Bet
-	(*BetWin)(nil),       // 1: sgc7pb.BetWin
-	(*GameModParam)(nil), // 2: sgc7pb.GameModParam
-	(*BetRequest)(nil),   // 3: sgc7pb.BetRequest
+	(*Bet)(nil),                  // 0: sgc7pb.Bet
+	(*BetWin)(nil),               // 1: sgc7pb.BetWin
+	(*WheelAnimation)(nil),       // 2: sgc7pb.WheelAnimation
+	(*GameModParam)(nil),         // 3: sgc7pb.GameModParam
+	(*BetRequest)(nil),           // 4: sgc7pb.BetRequest
+	(*BetLayout)(nil),            // 5: sgc7pb.BetLayout
+	(*RoulettePublicState)(nil),  // 6: sgc7pb.RoulettePublicState
+	(*RoulettePrivateState)(nil), // 7: sgc7pb.RoulettePrivateState
+	(*FairProof)(nil),            // 8: sgc7pb.FairProof
 }
 var file_proto_roulette_proto_depIdxs = []int32{
-	0, // 0: sgc7pb.BetWin.bet:type_name -> sgc7pb.Bet
-	1, // 1: sgc7pb.GameModParam.wins:type_name -> sgc7pb.BetWin
-	0, // 2: sgc7pb.BetRequest.bets:type_name -> sgc7pb.Bet
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0,  // 0: sgc7pb.BetWin.bet:type_name -> sgc7pb.Bet
+	1,  // 1: sgc7pb.GameModParam.wins:type_name -> sgc7pb.BetWin
+	2,  // 2: sgc7pb.GameModParam.wheel:type_name -> sgc7pb.WheelAnimation
+	8,  // 3: sgc7pb.GameModParam.fair:type_name -> sgc7pb.FairProof
+	0,  // 4: sgc7pb.GameModParam.imprisoned:type_name -> sgc7pb.Bet
+	1,  // 5: sgc7pb.GameModParam.released:type_name -> sgc7pb.BetWin
+	0,  // 6: sgc7pb.BetRequest.bets:type_name -> sgc7pb.Bet
+	0,  // 7: sgc7pb.BetLayout.bets:type_name -> sgc7pb.Bet
+	5,  // 8: sgc7pb.RoulettePublicState.favourites:type_name -> sgc7pb.BetLayout
+	0,  // 9: sgc7pb.RoulettePublicState.lastBets:type_name -> sgc7pb.Bet
+	0,  // 10: sgc7pb.RoulettePrivateState.enPrison:type_name -> sgc7pb.Bet
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_proto_roulette_proto_init() }
@@ -313,13 +738,14 @@ func file_proto_roulette_proto_init() {
 	if File_proto_roulette_proto != nil {
 		return
 	}
+	file_proto_fair_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_roulette_proto_rawDesc), len(file_proto_roulette_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,253 @@
+package rng
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Source 随机数来源, 与 game.RNGClient 相同
+type Source interface {
+	GetRandomNumber(r int) (uint32, error)
+	GetRandomNumbers(nums int32, r int) ([]uint32, error)
+	ScalingRandom(rngs []uint32, r int) (uint32, []uint32, error)
+}
+
+// RawSource 可以直接取原始 32 位随机数的来源
+type RawSource interface {
+	GetRawNumbers(nums int32) ([]uint32, error)
+}
+
+// Draw 一次随机数消耗
+type Draw struct {
+	Raw      uint32   `json:"raw"`                // 被采用的原始 32 位随机数
+	Range    int      `json:"range"`              // 缩放范围
+	Result   uint32   `json:"result"`             // 缩放结果, Raw % Range
+	Rejected []uint32 `json:"rejected,omitempty"` // 在 Raw 之前因无偏缩放被拒绝的原始随机数
+}
+
+// Recorder 记录经过它的每次随机数消耗, 实现 game.RNGClient
+//
+// 每局创建一个, 局结束后用 Draws 取出记录. 来源实现 RawSource 时
+// Recorder 自己逐个取原始随机数做拒绝采样, 被拒绝的值也会记录下来;
+// 逐个取数与来源自己缩放时消耗的序列相同.
+type Recorder struct {
+	src   Source // 为 nil 时使用 crypto/rand
+	mu    sync.Mutex
+	draws []Draw
+}
+
+// NewRecorder 包装 src, src 为 nil 时直接从 crypto/rand 取原始随机数
+func NewRecorder(src Source) *Recorder {
+	return &Recorder{src: src}
+}
+
+func (r *Recorder) record(raw uint32, n int, rejected []uint32) {
+	r.mu.Lock()
+	r.draws = append(r.draws, Draw{Raw: raw, Range: n, Result: raw % uint32(n), Rejected: rejected})
+	r.mu.Unlock()
+}
+
+// Draws 返回目前为止的所有消耗
+func (r *Recorder) Draws() []Draw {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Draw(nil), r.draws...)
+}
+
+// next 取一个原始随机数, ok 为 false 表示来源不能提供原始随机数
+func (r *Recorder) next() (v uint32, ok bool, err error) {
+	if r.src == nil {
+		var b [4]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return 0, true, err
+		}
+		return binary.LittleEndian.Uint32(b[:]), true, nil
+	}
+	raw, isRaw := r.src.(RawSource)
+	if !isRaw {
+		return 0, false, nil
+	}
+	vals, err := raw.GetRawNumbers(1)
+	if err != nil {
+		return 0, true, err
+	}
+	if len(vals) == 0 {
+		return 0, true, fmt.Errorf("RNG returned no numbers")
+	}
+	return vals[0], true, nil
+}
+
+// draw 从 pending 和来源中取出下一个可无偏缩放到 [0, n) 的原始随机数并记录
+func (r *Recorder) draw(pending []uint32, n int) (uint32, []uint32, error) {
+	var rejected []uint32
+	for {
+		var v uint32
+		if len(pending) > 0 {
+			v, pending = pending[0], pending[1:]
+		} else {
+			var ok bool
+			var err error
+			if v, ok, err = r.next(); err != nil {
+				return 0, pending, err
+			} else if !ok {
+				// 来源自己做拒绝采样, 只能记录被采用的值
+				v, err = r.src.GetRandomNumber(n)
+				if err != nil {
+					return 0, pending, err
+				}
+			}
+		}
+		if _, ok := ScaleUniform(v, uint32(n)); ok {
+			r.record(v, n, rejected)
+			return v, pending, nil
+		}
+		rejected = append(rejected, v)
+	}
+}
+
+func (r *Recorder) GetRandomNumber(n int) (uint32, error) {
+	v, _, err := r.draw(nil, n)
+	return v, err
+}
+
+func (r *Recorder) GetRandomNumbers(nums int32, n int) ([]uint32, error) {
+	out := make([]uint32, 0, nums)
+	for i := 0; i < int(nums); i++ {
+		v, err := r.GetRandomNumber(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (r *Recorder) ScalingRandom(rngs []uint32, n int) (uint32, []uint32, error) {
+	return r.draw(rngs, n)
+}
+
+// GetRawNumbers 一次取出 nums 个原始随机数, 取出时不记录, 交给 ScalingRandom 消耗时才记录.
+// 来源不能提供原始随机数时返回空, ScalingRandom 再逐个向来源取数
+func (r *Recorder) GetRawNumbers(nums int32) ([]uint32, error) {
+	if r.src == nil {
+		b := make([]byte, 4*nums)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		out := make([]uint32, nums)
+		for i := range out {
+			out[i] = binary.LittleEndian.Uint32(b[4*i:])
+		}
+		return out, nil
+	}
+	raw, isRaw := r.src.(RawSource)
+	if !isRaw {
+		return nil, nil
+	}
+	return raw.GetRawNumbers(nums)
+}
+
+// AuditRecord 审计日志中的一条记录, 对应一次随机数消耗
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Gamecode string    `json:"gamecode"`
+	Table    string    `json:"table"`
+	Round    uint64    `json:"round"`
+	Seq      int       `json:"seq"`    // 该局内的顺序, 从 0 开始
+	Source   string    `json:"source"` // rng, local 或 seeded
+	Draw
+}
+
+// AuditLog 追加写入的随机数审计日志, 每行一条 JSON
+type AuditLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenAuditLog 以追加方式打开审计日志
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RNG audit log: %v", err)
+	}
+	return &AuditLog{f: f}, nil
+}
+
+// Append 写入一局的所有消耗, 一次写入并落盘
+func (l *AuditLog) Append(gamecode, table string, round uint64, source string, draws []Draw) error {
+	now := time.Now()
+	var buf []byte
+	for i, d := range draws {
+		line, err := json.Marshal(AuditRecord{
+			Time:     now,
+			Gamecode: gamecode,
+			Table:    table,
+			Round:    round,
+			Seq:      i,
+			Source:   source,
+			Draw:     d,
+		})
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(buf); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// Close 关闭审计日志
+func (l *AuditLog) Close() error {
+	return l.f.Close()
+}
+
+// ReadAuditLog 按顺序读出审计日志中的每条记录
+func ReadAuditLog(path string, fn func(*AuditRecord) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if err := fn(&rec); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// QueryAudit 返回某张桌子某一局消耗的所有随机数
+func QueryAudit(path, table string, round uint64) ([]AuditRecord, error) {
+	var out []AuditRecord
+	err := ReadAuditLog(path, func(rec *AuditRecord) error {
+		if rec.Table == table && rec.Round == round {
+			out = append(out, *rec)
+		}
+		return nil
+	})
+	return out, err
+}
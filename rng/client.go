@@ -3,16 +3,23 @@ package rng
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"gitee.com/heartfun/rouletteserv/proto"
 	"github.com/rs/zerolog/log"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // RNGClient 随机数生成客户端
 type RNGClient struct {
-	conn   *grpc.ClientConn
-	client proto.RngClient
+	fo       *failover // 远程RNG服务, 进程内RNG和 Stream 返回的客户端为 nil
+	client   proto.RngClient
+	gamecode string
+	table    string
+	timeout  time.Duration
+	pool     *pool
 }
 
 const (
@@ -20,46 +27,165 @@ const (
 )
 
 // NewRNGClient 创建新的RNG客户端
+// addr 可以是逗号分隔的多个地址, 默认 fail closed, 只使用第一个
 func NewRNGClient(addr string) (*RNGClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	return NewRNGClientWithConfig(ClientConfig{Addrs: SplitAddrs(addr)})
+}
+
+// NewRNGClientWithConfig 创建连接多个RNG服务的客户端, 按 cfg.Policy 重试和切换
+func NewRNGClientWithConfig(cfg ClientConfig) (*RNGClient, error) {
+	fo, err := newFailover(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RNG service: %v", err)
+		return nil, err
 	}
 
 	return &RNGClient{
-		conn:   conn,
-		client: proto.NewRngClient(conn),
+		fo:       fo,
+		client:   fo,
+		gamecode: GameCode,
+		timeout:  DefaultCallTimeout,
 	}, nil
 }
 
-// GetRandomNumber 获取随机数
-func (c *RNGClient) GetRandomNumber(r int) (uint32, error) {
-	ctx := context.Background()
+// Endpoints 返回每个RNG服务的健康状态, 进程内RNG返回 nil
+func (c *RNGClient) Endpoints() []EndpointStatus {
+	if c.fo == nil {
+		return nil
+	}
+	return c.fo.status()
+}
+
+// Stream 返回使用 gamecode/table 独立随机数流的客户端, 与 c 共用连接和切换策略
+// 只需要关闭原来的客户端, 预取池不会被共用
+func (c *RNGClient) Stream(gamecode, table string) *RNGClient {
+	return &RNGClient{
+		client:   c.client,
+		gamecode: gamecode,
+		table:    table,
+		timeout:  c.timeout,
+	}
+}
+
+// Prefetch 开启本地随机数池, 在后台按水位补充, Close 时停止
+// cfg.High 为 0 时只设置调用超时
+func (c *RNGClient) Prefetch(cfg PoolConfig) *RNGClient {
+	if cfg.Timeout > 0 {
+		c.timeout = cfg.Timeout
+	}
+	if cfg.High > 0 && c.pool == nil {
+		c.pool = newPool(cfg, c.fetchBulk)
+	}
+	return c
+}
+
+// PoolStats 返回预取池指标, 没有开启时返回零值
+func (c *RNGClient) PoolStats() PoolStats {
+	if c.pool == nil {
+		return PoolStats{}
+	}
+	return c.pool.snapshot()
+}
+
+// fetch 向RNG服务请求 n 个原始随机数, 带超时
+func (c *RNGClient) fetch(n int) ([]uint32, error) {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
 	resp, err := c.client.GetRngs(ctx, &proto.RequestRngs{
-		Nums:     0,
-		Gamecode: GameCode,
+		Nums:     int32(n),
+		Gamecode: c.gamecode,
+		Table:    c.table,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Rngs) < n {
+		return nil, fmt.Errorf("RNG service returned %d of %d numbers", len(resp.Rngs), n)
+	}
+	return resp.Rngs, nil
+}
+
+// BulkThreshold 一次至少取这么多时使用流式请求
+const BulkThreshold = 512
+
+// fetchStream 用流式请求取 n 个原始随机数, 带超时
+func (c *RNGClient) fetchStream(n int) ([]uint32, error) {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	st, err := c.client.StreamRngs(ctx, &proto.RequestRngs{
+		Nums:     int32(n),
+		Gamecode: c.gamecode,
+		Table:    c.table,
 	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint32, 0, n)
+	for {
+		reply, err := st.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, reply.Rngs...)
+	}
+	if len(out) < n {
+		return nil, fmt.Errorf("RNG service streamed %d of %d numbers", len(out), n)
+	}
+	return out, nil
+}
+
+// raw 取 n 个原始随机数, 开启预取时优先从本地池取, 大批量时使用流式请求
+func (c *RNGClient) raw(n int) ([]uint32, error) {
+	if n <= 0 {
+		n = 1
+	}
+	if c.pool != nil {
+		return c.pool.take(n)
+	}
+	return c.fetchBulk(n)
+}
+
+// fetchBulk 按数量选择普通请求或流式请求
+func (c *RNGClient) fetchBulk(n int) ([]uint32, error) {
+	if n >= BulkThreshold {
+		vals, err := c.fetchStream(n)
+		// 旧版本RNG服务没有流式接口, 退回普通请求
+		if status.Code(err) != codes.Unimplemented {
+			return vals, err
+		}
+	}
+	return c.fetch(n)
+}
+
+// GetRandomNumber 获取随机数
+func (c *RNGClient) GetRandomNumber(r int) (uint32, error) {
+	rn, _, err := c.ScalingRandom(nil, r)
 	if err != nil {
 		log.Err(err).Msg("Failed to get random number")
-		return 0, err
 	}
-	rn, _, err := c.ScalingRandom(resp.Rngs, r)
 	return rn, err
 }
 
 // GetRandomNumbers 获取随机数
 func (c *RNGClient) GetRandomNumbers(nums int32, r int) ([]uint32, error) {
-	ctx := context.Background()
-	resp, err := c.client.GetRngs(ctx, &proto.RequestRngs{
-		Nums:     nums,
-		Gamecode: GameCode,
-	})
+	rngs, err := c.raw(int(nums))
 	if err != nil {
 		log.Err(err).Msg("Failed to get random number")
 		return []uint32{0}, err
 	}
 	rnArr := make([]uint32, 0, nums)
-	rngs := resp.Rngs
 	for i := 0; i < int(nums); i++ {
 		rn, remainRngs, err := c.ScalingRandom(rngs, r)
 		if err != nil {
@@ -72,51 +198,42 @@ func (c *RNGClient) GetRandomNumbers(nums int32, r int) ([]uint32, error) {
 	return rnArr, nil
 }
 
-// ScalingRandom [0, r)
-func (c *RNGClient) ScalingRandom(rngs []uint32, r int) (uint32, []uint32, error) {
-	ctx := context.Background()
-	curRngs := append([]uint32(nil), rngs...)
-	if len(curRngs) == 0 {
-		resp, err := c.client.GetRngs(ctx, &proto.RequestRngs{
-			Nums:     0,
-			Gamecode: GameCode,
-		})
-		if err != nil {
-			return 0, []uint32{0}, err
-		}
-		curRngs = resp.Rngs
-	}
-
-	maxval := int64(r)
-
-	cr := uint32(0)
-	MAX_RANGE := int64(1) << 32
-	limit := MAX_RANGE - (MAX_RANGE % maxval)
+// GetRawNumbers 获取 nums 个完整的 32 位原始随机数, 不做缩放
+func (c *RNGClient) GetRawNumbers(nums int32) ([]uint32, error) {
+	return c.raw(int(nums))
+}
 
+// ScalingRandom 取出下一个可无偏缩放到 [0, r) 的原始随机数, 调用方对 r 取模
+func (c *RNGClient) ScalingRandom(rngs []uint32, r int) (uint32, []uint32, error) {
+	// 只做切片, 不会修改调用方的数组
+	curRngs := rngs
 	for {
 		if len(curRngs) == 0 {
-			resp, err := c.client.GetRngs(ctx, &proto.RequestRngs{
-				Nums:     0,
-				Gamecode: GameCode,
-			})
+			more, err := c.raw(1)
 			if err != nil {
 				return 0, []uint32{0}, err
 			}
-			curRngs = resp.Rngs
+			curRngs = more
 		}
 
-		cr = curRngs[0]
+		cr := curRngs[0]
 		curRngs = curRngs[1:]
 
-		if int64(cr) < limit {
-			break
+		// 服务端返回完整的 32 位随机数, 拒绝最后一个不完整区间保证无偏
+		if _, ok := ScaleUniform(cr, uint32(r)); ok {
+			return cr, curRngs, nil
 		}
 	}
-
-	return cr, curRngs, nil
 }
 
-// Close 关闭连接
+// Close 停止预取并关闭连接
 func (c *RNGClient) Close() error {
-	return c.conn.Close()
+	if c.pool != nil {
+		c.pool.close()
+		c.pool = nil
+	}
+	if c.fo == nil {
+		return nil
+	}
+	return c.fo.Close()
 }
@@ -0,0 +1,100 @@
+package rng
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// DRBG 确定性随机数发生器, 仅用于回放和QA
+//
+// 第 i 块输出为 HMAC-SHA256(seed, i), 每块拆成 8 个大端 32 位数.
+// 相同种子总是得到相同的序列, 实现了 game.RNGClient 接口.
+type DRBG struct {
+	mu      sync.Mutex
+	seed    []byte
+	counter uint64
+	buf     []uint32
+}
+
+// NewDRBG 用种子创建确定性随机数发生器
+func NewDRBG(seed []byte) *DRBG {
+	return &DRBG{seed: append([]byte(nil), seed...)}
+}
+
+// RoundSeed 由主种子派生某张桌子某一局的种子
+func RoundSeed(master []byte, table string, round uint64) []byte {
+	mac := hmac.New(sha256.New, master)
+	fmt.Fprintf(mac, "%s:%d", table, round)
+	return mac.Sum(nil)
+}
+
+// next 返回下一个 32 位原始随机数, 调用方持有锁
+func (d *DRBG) next() uint32 {
+	if len(d.buf) == 0 {
+		mac := hmac.New(sha256.New, d.seed)
+		var ctr [8]byte
+		binary.BigEndian.PutUint64(ctr[:], d.counter)
+		mac.Write(ctr[:])
+		d.counter++
+		sum := mac.Sum(nil)
+		for i := 0; i+4 <= len(sum); i += 4 {
+			d.buf = append(d.buf, binary.BigEndian.Uint32(sum[i:i+4]))
+		}
+	}
+	v := d.buf[0]
+	d.buf = d.buf[1:]
+	return v
+}
+
+// GetRandomNumber 获取一个可无偏缩放到 [0, r) 的原始随机数
+func (d *DRBG) GetRandomNumber(r int) (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for {
+		v := d.next()
+		if _, ok := ScaleUniform(v, uint32(r)); ok {
+			return v, nil
+		}
+	}
+}
+
+// GetRandomNumbers 获取 nums 个可无偏缩放到 [0, r) 的原始随机数
+func (d *DRBG) GetRandomNumbers(nums int32, r int) ([]uint32, error) {
+	out := make([]uint32, 0, nums)
+	for i := 0; i < int(nums); i++ {
+		v, err := d.GetRandomNumber(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// GetRawNumbers 获取 nums 个原始 32 位随机数
+func (d *DRBG) GetRawNumbers(nums int32) ([]uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]uint32, nums)
+	for i := range out {
+		out[i] = d.next()
+	}
+	return out, nil
+}
+
+// ScalingRandom 从 rngs 中取出下一个可用的随机数, 不够时从发生器补充
+func (d *DRBG) ScalingRandom(rngs []uint32, r int) (uint32, []uint32, error) {
+	cur := append([]uint32(nil), rngs...)
+	for len(cur) > 0 {
+		v := cur[0]
+		cur = cur[1:]
+		if _, ok := ScaleUniform(v, uint32(r)); ok {
+			return v, cur, nil
+		}
+	}
+	v, err := d.GetRandomNumber(r)
+	return v, cur, err
+}
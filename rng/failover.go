@@ -0,0 +1,341 @@
+package rng
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 多个RNG服务时的策略
+const (
+	// PolicyFailClosed 只使用第一个服务, 不可用时返回错误, 由调用方暂停牌桌
+	PolicyFailClosed = "failclosed"
+	// PolicyFailover 按顺序使用第一个健康的服务, 主服务恢复后切回
+	PolicyFailover = "failover"
+)
+
+// 默认重试和健康检查参数
+const (
+	DefaultRetries        = 2
+	DefaultBackoff        = 50 * time.Millisecond
+	DefaultHealthInterval = 2 * time.Second
+)
+
+// ClientConfig 多RNG服务客户端配置
+type ClientConfig struct {
+	Addrs          []string      // 按优先级排列的RNG服务地址
+	Policy         string        // PolicyFailClosed (默认) 或 PolicyFailover
+	Retries        int           // 同一服务上的重试次数, 0 为默认值, 负数不重试
+	Backoff        time.Duration // 第一次重试前的等待, 之后每次翻倍, 0 为默认值
+	HealthInterval time.Duration // 健康检查间隔, 0 为默认值
+}
+
+// SplitAddrs 解析逗号分隔的地址列表
+func SplitAddrs(s string) []string {
+	var addrs []string
+	for _, a := range strings.Split(s, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+// EndpointStatus 一个RNG服务的状态
+type EndpointStatus struct {
+	Addr    string
+	Healthy bool
+	Active  bool // 最近一次成功请求使用的服务
+	LastErr string
+}
+
+// endpoint 一个RNG服务连接
+type endpoint struct {
+	addr   string
+	conn   *grpc.ClientConn
+	client proto.RngClient
+
+	mu      sync.Mutex
+	healthy bool
+	lastErr error
+}
+
+func (e *endpoint) mark(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		e.healthy = true
+		return
+	}
+	if e.healthy {
+		log.Warn().Err(err).Str("addr", e.addr).Msg("RNG service unhealthy")
+	}
+	e.healthy = false
+	e.lastErr = err
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy
+}
+
+// failover 以 proto.RngClient 的形式按策略在多个RNG服务之间重试和切换
+type failover struct {
+	eps      []*endpoint
+	policy   string
+	retries  int
+	backoff  time.Duration
+	interval time.Duration
+
+	mu     sync.Mutex
+	active *endpoint
+	done   chan struct{}
+}
+
+func newFailover(cfg ClientConfig) (*failover, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, fmt.Errorf("no RNG service address")
+	}
+	switch cfg.Policy {
+	case "":
+		cfg.Policy = PolicyFailClosed
+	case PolicyFailClosed, PolicyFailover:
+	default:
+		return nil, fmt.Errorf("unknown RNG policy %q, want %s or %s", cfg.Policy, PolicyFailClosed, PolicyFailover)
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = DefaultRetries
+	} else if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultBackoff
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = DefaultHealthInterval
+	}
+
+	f := &failover{
+		policy:   cfg.Policy,
+		retries:  cfg.Retries,
+		backoff:  cfg.Backoff,
+		interval: cfg.HealthInterval,
+		done:     make(chan struct{}),
+	}
+	for _, addr := range cfg.Addrs {
+		conn, err := grpc.Dial(addr, grpc.WithInsecure())
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to connect to RNG service %s: %v", addr, err)
+		}
+		f.eps = append(f.eps, &endpoint{
+			addr:    addr,
+			conn:    conn,
+			client:  proto.NewRngClient(conn),
+			healthy: true,
+		})
+	}
+	go f.watch()
+	return f, nil
+}
+
+// candidates 本次请求按顺序尝试的服务
+func (f *failover) candidates() []*endpoint {
+	if f.policy == PolicyFailClosed {
+		return f.eps[:1]
+	}
+	// 健康的在前, 都不健康时仍按顺序尝试
+	out := make([]*endpoint, 0, len(f.eps))
+	for _, e := range f.eps {
+		if e.isHealthy() {
+			out = append(out, e)
+		}
+	}
+	for _, e := range f.eps {
+		if !e.isHealthy() {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// use 记录当前使用的服务, 切换时留下日志
+func (f *failover) use(e *endpoint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active == e {
+		return
+	}
+	if f.active != nil {
+		log.Warn().Str("from", f.active.addr).Str("to", e.addr).Str("policy", f.policy).Msg("RNG service switched")
+	}
+	f.active = e
+}
+
+// retryable 连接类错误可以重试或切换, 其它错误直接返回
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+func (f *failover) GetRngs(ctx context.Context, in *proto.RequestRngs, opts ...grpc.CallOption) (*proto.ReplyRngs, error) {
+	var lastErr error
+	for _, e := range f.candidates() {
+		for attempt := 0; attempt <= f.retries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+				case <-time.After(f.backoff << (attempt - 1)):
+				}
+			}
+			reply, err := e.client.GetRngs(ctx, in, opts...)
+			if err == nil {
+				e.mark(nil)
+				f.use(e)
+				return reply, nil
+			}
+			if !retryable(err) {
+				return nil, err
+			}
+			lastErr = err
+		}
+		e.mark(lastErr)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, status.Errorf(codes.Unavailable, "no RNG service available (%s): %v", f.policy, lastErr)
+}
+
+// StreamRngs 在第一个能返回数据的服务上打开流, 收到第一块之后的错误直接交给调用方
+func (f *failover) StreamRngs(ctx context.Context, in *proto.RequestRngs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.ReplyRngs], error) {
+	var lastErr error
+	for _, e := range f.candidates() {
+		for attempt := 0; attempt <= f.retries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+				case <-time.After(f.backoff << (attempt - 1)):
+				}
+			}
+			st, err := e.client.StreamRngs(ctx, in, opts...)
+			if err == nil {
+				var first *proto.ReplyRngs
+				if first, err = st.Recv(); err == nil {
+					e.mark(nil)
+					f.use(e)
+					return &primedStream{ServerStreamingClient: st, first: first}, nil
+				}
+			}
+			if !retryable(err) {
+				return nil, err
+			}
+			lastErr = err
+		}
+		e.mark(lastErr)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, status.Errorf(codes.Unavailable, "no RNG service available (%s): %v", f.policy, lastErr)
+}
+
+// primedStream 先返回已经收到的第一块
+type primedStream struct {
+	grpc.ServerStreamingClient[proto.ReplyRngs]
+	first *proto.ReplyRngs
+}
+
+func (p *primedStream) Recv() (*proto.ReplyRngs, error) {
+	if r := p.first; r != nil {
+		p.first = nil
+		return r, nil
+	}
+	return p.ServerStreamingClient.Recv()
+}
+
+// Health 返回当前服务的熵源状态
+func (f *failover) Health(ctx context.Context, in *proto.RequestHealth, opts ...grpc.CallOption) (*proto.ReplyHealth, error) {
+	return f.candidates()[0].client.Health(ctx, in, opts...)
+}
+
+// watch 定期检查每个服务的熵源健康状态
+func (f *failover) watch() {
+	t := time.NewTicker(f.interval)
+	defer t.Stop()
+	for {
+		for _, e := range f.eps {
+			e.mark(f.check(e))
+		}
+		select {
+		case <-f.done:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (f *failover) check(e *endpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.interval)
+	defer cancel()
+	reply, err := e.client.Health(ctx, &proto.RequestHealth{})
+	if status.Code(err) == codes.Unimplemented {
+		// 旧版本RNG服务没有健康检测
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !reply.Ok {
+		return fmt.Errorf("entropy source unhealthy: %s", reply.Error)
+	}
+	return nil
+}
+
+func (f *failover) status() []EndpointStatus {
+	f.mu.Lock()
+	active := f.active
+	f.mu.Unlock()
+
+	out := make([]EndpointStatus, 0, len(f.eps))
+	for _, e := range f.eps {
+		e.mu.Lock()
+		st := EndpointStatus{Addr: e.addr, Healthy: e.healthy, Active: e == active}
+		if e.lastErr != nil {
+			st.LastErr = e.lastErr.Error()
+		}
+		e.mu.Unlock()
+		out = append(out, st)
+	}
+	return out
+}
+
+// Close 停止健康检查并关闭所有连接
+func (f *failover) Close() error {
+	select {
+	case <-f.done:
+	default:
+		close(f.done)
+	}
+	var first error
+	for _, e := range f.eps {
+		if err := e.conn.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,297 @@
+package rng
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// 可选的随机数发生器算法
+const (
+	AlgCrypto   = "crypto"    // 直接使用经过健康检测的 crypto/rand
+	AlgChaCha20 = "chacha20"  // ChaCha20 密钥流, 每次输出后更换密钥
+	AlgHMACDRBG = "hmac_drbg" // NIST SP 800-90A HMAC_DRBG (SHA-256)
+	AlgCTRDRBG  = "ctr_drbg"  // NIST SP 800-90A CTR_DRBG (AES-256, 无派生函数)
+)
+
+// Algorithms 所有支持的算法
+var Algorithms = []string{AlgCrypto, AlgChaCha20, AlgHMACDRBG, AlgCTRDRBG}
+
+// 默认重新播种策略, 任一条件满足即从系统熵源重新播种
+const (
+	DefaultReseedInterval = 1 << 16     // 生成请求次数
+	DefaultReseedPeriod   = time.Minute // 时间间隔
+)
+
+// maxRequestBytes SP 800-90A 每次生成请求的上限 (2^19 位)
+const maxRequestBytes = 1 << 16
+
+// Generator 随机数发生器后端
+type Generator interface {
+	// Name 算法名, 会在应答中返回
+	Name() string
+	// Read 填满 buf, 失败时不输出任何可用数据
+	Read(buf []byte) error
+	// Reseeds 从系统熵源重新播种的次数
+	Reseeds() uint64
+}
+
+// NewGenerator 按算法名创建发生器, 种子全部来自 src
+func NewGenerator(alg string, src *entropySource, interval uint64, period time.Duration) (Generator, error) {
+	if interval == 0 {
+		interval = DefaultReseedInterval
+	}
+	if period <= 0 {
+		period = DefaultReseedPeriod
+	}
+	rs := reseeder{src: src, interval: interval, period: period}
+
+	var g drbg
+	switch alg {
+	case "", AlgCrypto:
+		return cryptoGen{src: src}, nil
+	case AlgChaCha20:
+		g = &chachaDRBG{}
+	case AlgHMACDRBG:
+		g = &hmacDRBG{}
+	case AlgCTRDRBG:
+		g = &ctrDRBG{}
+	default:
+		return nil, fmt.Errorf("unknown RNG algorithm %q, want one of %v", alg, Algorithms)
+	}
+	d := &seededGen{drbg: g, reseeder: rs}
+	if err := d.reseed(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// cryptoGen 直接输出经过检测的系统熵
+type cryptoGen struct {
+	src *entropySource
+}
+
+func (c cryptoGen) Name() string          { return AlgCrypto }
+func (c cryptoGen) Read(buf []byte) error { return c.src.fill(buf) }
+func (c cryptoGen) Reseeds() uint64       { return 0 }
+
+// drbg 确定性发生器的核心算法, 由 seededGen 负责加锁和重新播种
+type drbg interface {
+	name() string
+	seedLen() int
+	seed(entropy []byte)
+	generate(out []byte)
+}
+
+// reseeder 记录距离上次播种的请求次数和时间
+type reseeder struct {
+	src      *entropySource
+	interval uint64
+	period   time.Duration
+	counter  uint64
+	seededAt time.Time
+	reseeds  uint64
+}
+
+func (r *reseeder) due() bool {
+	return r.counter >= r.interval || time.Since(r.seededAt) >= r.period
+}
+
+// seededGen 周期性从系统熵源重新播种的确定性发生器
+type seededGen struct {
+	mu sync.Mutex
+	drbg
+	reseeder
+}
+
+func (g *seededGen) Name() string { return g.name() }
+
+func (g *seededGen) Reseeds() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.reseeds
+}
+
+// reseed 调用方持有锁或处于初始化阶段
+func (g *seededGen) reseed() error {
+	entropy := make([]byte, g.seedLen())
+	if err := g.src.fill(entropy); err != nil {
+		return err
+	}
+	g.seed(entropy)
+	g.counter = 0
+	g.seededAt = time.Now()
+	g.reseeds++
+	return nil
+}
+
+func (g *seededGen) Read(buf []byte) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for len(buf) > 0 {
+		if g.due() {
+			// 熵源失败时拒绝继续输出, 不沿用旧状态
+			if err := g.reseed(); err != nil {
+				return err
+			}
+		}
+		n := min(len(buf), maxRequestBytes)
+		g.generate(buf[:n])
+		g.counter++
+		buf = buf[n:]
+	}
+	return nil
+}
+
+// chachaDRBG 快速密钥擦除的 ChaCha20 发生器
+//
+// 每次请求先输出 32 字节作为下一次的密钥, 旧密钥立即丢弃.
+type chachaDRBG struct {
+	key [chacha20.KeySize]byte
+}
+
+func (c *chachaDRBG) name() string { return AlgChaCha20 }
+func (c *chachaDRBG) seedLen() int { return chacha20.KeySize }
+
+func (c *chachaDRBG) seed(entropy []byte) {
+	// 新密钥 = 旧密钥 XOR 新熵, 首次播种时旧密钥为 0
+	for i := range c.key {
+		c.key[i] ^= entropy[i]
+	}
+}
+
+func (c *chachaDRBG) generate(out []byte) {
+	var nonce [chacha20.NonceSize]byte
+	s, _ := chacha20.NewUnauthenticatedCipher(c.key[:], nonce[:])
+	var next [chacha20.KeySize]byte
+	s.XORKeyStream(next[:], next[:])
+	clear(out)
+	s.XORKeyStream(out, out)
+	c.key = next
+}
+
+// hmacDRBG NIST SP 800-90A 10.1.2 HMAC_DRBG, SHA-256
+type hmacDRBG struct {
+	k, v []byte
+}
+
+func (h *hmacDRBG) name() string { return AlgHMACDRBG }
+
+// seedLen 熵输入 32 字节加 16 字节 nonce
+func (h *hmacDRBG) seedLen() int { return 48 }
+
+func (h *hmacDRBG) seed(entropy []byte) {
+	if h.k == nil {
+		// 实例化
+		h.k = make([]byte, sha256.Size)
+		h.v = make([]byte, sha256.Size)
+		for i := range h.v {
+			h.v[i] = 0x01
+		}
+	}
+	h.update(entropy)
+}
+
+func (h *hmacDRBG) mac(parts ...[]byte) []byte {
+	m := hmac.New(sha256.New, h.k)
+	for _, p := range parts {
+		m.Write(p)
+	}
+	return m.Sum(nil)
+}
+
+// update HMAC_DRBG_Update
+func (h *hmacDRBG) update(provided []byte) {
+	h.k = h.mac(h.v, []byte{0x00}, provided)
+	h.v = h.mac(h.v)
+	if len(provided) == 0 {
+		return
+	}
+	h.k = h.mac(h.v, []byte{0x01}, provided)
+	h.v = h.mac(h.v)
+}
+
+func (h *hmacDRBG) generate(out []byte) {
+	for off := 0; off < len(out); off += sha256.Size {
+		h.v = h.mac(h.v)
+		copy(out[off:], h.v)
+	}
+	h.update(nil)
+}
+
+// ctrDRBG NIST SP 800-90A 10.2.1 CTR_DRBG, AES-256, 不使用派生函数
+type ctrDRBG struct {
+	key [32]byte
+	v   [aes.BlockSize]byte
+}
+
+func (c *ctrDRBG) name() string { return AlgCTRDRBG }
+
+// seedLen 密钥长度加分组长度
+func (c *ctrDRBG) seedLen() int { return 32 + aes.BlockSize }
+
+func (c *ctrDRBG) seed(entropy []byte) {
+	// 首次播种时 key 和 v 都是 0, 即实例化
+	c.update(entropy)
+}
+
+func (c *ctrDRBG) block() cipher.Block {
+	b, _ := aes.NewCipher(c.key[:])
+	return b
+}
+
+// incV V = (V+1) mod 2^128
+func (c *ctrDRBG) incV() {
+	lo := binary.BigEndian.Uint64(c.v[8:]) + 1
+	binary.BigEndian.PutUint64(c.v[8:], lo)
+	if lo == 0 {
+		binary.BigEndian.PutUint64(c.v[:8], binary.BigEndian.Uint64(c.v[:8])+1)
+	}
+}
+
+// update CTR_DRBG_Update, provided 为空或 seedLen 字节
+func (c *ctrDRBG) update(provided []byte) {
+	b := c.block()
+	temp := make([]byte, c.seedLen())
+	for off := 0; off < len(temp); off += aes.BlockSize {
+		c.incV()
+		b.Encrypt(temp[off:], c.v[:])
+	}
+	for i := range provided {
+		temp[i] ^= provided[i]
+	}
+	copy(c.key[:], temp[:32])
+	copy(c.v[:], temp[32:])
+}
+
+func (c *ctrDRBG) generate(out []byte) {
+	b := c.block()
+	var blk [aes.BlockSize]byte
+	for off := 0; off < len(out); off += aes.BlockSize {
+		c.incV()
+		b.Encrypt(blk[:], c.v[:])
+		copy(out[off:], blk[:])
+	}
+	c.update(nil)
+}
+
+// readUint32s 从发生器读取 n 个 32 位随机数
+func readUint32s(g Generator, n int) ([]uint32, error) {
+	buf := make([]byte, 4*n)
+	if err := g.Read(buf); err != nil {
+		return nil, err
+	}
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = binary.LittleEndian.Uint32(buf[4*i:])
+	}
+	return out, nil
+}
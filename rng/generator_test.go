@@ -0,0 +1,84 @@
+package rng
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestHMACDRBGVectors NIST CAVP HMAC_DRBG.rsp, [SHA-256], no prediction
+// resistance, no personalization string or additional input, 1024 returned
+// bits: instantiate, (reseed,) generate twice, compare the second output.
+func TestHMACDRBGVectors(t *testing.T) {
+	tests := []struct {
+		name, entropy, nonce, reseed, want string
+	}{
+		{
+			name:    "no_reseed COUNT 0",
+			entropy: "ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488",
+			nonce:   "659ba96c601dc69fc902940805ec0ca8",
+			want: "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89" +
+				"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1" +
+				"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668" +
+				"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8",
+		},
+		{
+			name:    "pr_false COUNT 0",
+			entropy: "06032cd5eed33f39265f49ecb142c511da9aff2af71203bffaf34a9ca5bd9c0d",
+			nonce:   "0e66f71edc43e42a45ad3c6fc6cdc4df",
+			reseed:  "01920a4e669ed3a85ae8a33b35a74ad7fb2a6bb4cf395ce00334a9c9a5a5d552",
+			want: "76fc79fe9b50beccc991a11b5635783a83536add03c157fb30645e611c2898bb" +
+				"2b1bc215000209208cd506cb28da2a51bdb03826aaf2bd2335d576d519160842" +
+				"e7158ad0949d1a9ec3e66ea1b1a064b005de914eac2e9d4f2d72a8616a802254" +
+				"22918250ff66a41bd2f864a6a38cc5b6499dc43f7f2bd09e1e0f8f5885935124",
+		},
+	}
+	for _, tt := range tests {
+		h := &hmacDRBG{}
+		h.seed(append(unhex(t, tt.entropy), unhex(t, tt.nonce)...))
+		if tt.reseed != "" {
+			h.seed(unhex(t, tt.reseed))
+		}
+		got := make([]byte, 1024/8)
+		h.generate(got)
+		h.generate(got)
+		if want := unhex(t, tt.want); !bytes.Equal(got, want) {
+			t.Errorf("%s: ReturnedBits = %x, want %x", tt.name, got, want)
+		}
+	}
+}
+
+// TestCTRDRBGVectors NIST CAVP CTR_DRBG.rsp, [AES-256 no df], no prediction
+// resistance, no personalization string or additional input, 512 returned
+// bits: instantiate, generate twice, compare the second output.
+func TestCTRDRBGVectors(t *testing.T) {
+	tests := []struct {
+		name, entropy, want string
+	}{
+		{
+			name:    "no_reseed COUNT 0",
+			entropy: "df5d73faa468649edda33b5cca79b0b05600419ccb7a879ddfec9db32ee494e5531b51de16a30f769262474c73bec010",
+			want: "d1c07cd95af8a7f11012c84ce48bb8cb87189e99d40fccb1771c619bdf82ab22" +
+				"80b1dc2f2581f39164f7ac0c510494b3a43c41b7db17514c87b107ae793e01c5",
+		},
+	}
+	for _, tt := range tests {
+		c := &ctrDRBG{}
+		c.seed(unhex(t, tt.entropy))
+		got := make([]byte, 512/8)
+		c.generate(got)
+		c.generate(got)
+		if want := unhex(t, tt.want); !bytes.Equal(got, want) {
+			t.Errorf("%s: ReturnedBits = %x, want %x", tt.name, got, want)
+		}
+	}
+}
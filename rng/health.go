@@ -0,0 +1,263 @@
+package rng
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// 连续健康检测参数 (NIST SP 800-90B 4.4), 检测对象为熵源输出的每个字节
+//
+// 正常熵源的误报率: RCT 阈值为 5, 每个字节与前 4 个相同的概率为 2^-32, 约每 4 GiB 误报一次;
+// APT 每个 512 字节窗口误报概率不超过 2^-30, 约每 512 GiB 以上一次.
+// crypto 算法的每个输出字节都经过检测, 长期运行必然遇到误报; 确定性发生器只检测种子,
+// 误报很少. 因此失败后按 retestBackoff 起步、倍增到 maxRetestBackoff 的间隔自动重新执行
+// 开机检测, 误报只造成短暂停止输出, 真正故障的熵源则一直无法通过
+const (
+	// EntropyPerSample 每个字节声明的最小熵, 位
+	EntropyPerSample = 8.0
+	// healthAlphaBits 误报概率 α = 2^-30
+	healthAlphaBits = 30
+	// AptWindow 自适应比例检测窗口, 非二值样本
+	AptWindow = 512
+	// startupSamples 开机检测样本数
+	startupSamples = 1024
+	// retestBackoff 失败后第一次自动重新检测的等待时间
+	retestBackoff = time.Second
+	// maxRetestBackoff 自动重新检测的最长间隔; 恢复后运行超过该时间再失败, 间隔从头开始
+	maxRetestBackoff = 5 * time.Minute
+)
+
+// HealthStatus 健康检测状态
+type HealthStatus struct {
+	OK          bool
+	Err         error
+	FailedAt    time.Time
+	Samples     uint64
+	RctFailures uint64
+	AptFailures uint64
+	RctCutoff   int
+	AptCutoff   int
+	Retests     uint64    // 自动重新检测次数
+	NextRetest  time.Time // 失败时下一次自动重新检测的时间
+}
+
+// HealthTester 重复计数检测(RCT)和自适应比例检测(APT)
+// 任一检测失败后锁定为失败状态, 之后的样本一律拒绝, 直到调用 Reset
+// 熵源在失败后自动重新检测, 见 entropySource
+type HealthTester struct {
+	rctCutoff int
+	aptCutoff int
+
+	last     byte
+	run      int
+	aptRef   byte
+	aptCount int
+	aptIdx   int
+
+	status HealthStatus
+}
+
+// NewHealthTester 按每样本最小熵 h 计算检测阈值
+func NewHealthTester(h float64) *HealthTester {
+	t := &HealthTester{
+		rctCutoff: 1 + int(math.Ceil(healthAlphaBits/h)),
+		aptCutoff: aptCutoff(AptWindow, math.Pow(2, -h), math.Pow(2, -healthAlphaBits)),
+	}
+	t.status.OK = true
+	t.status.RctCutoff = t.rctCutoff
+	t.status.AptCutoff = t.aptCutoff
+	return t
+}
+
+// aptCutoff 返回 1 + CRITBINOM(w, p, 1-α): 窗口内第一个样本之后的 w-1 个样本中
+// 与它相同的个数达到该值的概率不超过 α
+func aptCutoff(w int, p, alpha float64) int {
+	n := w - 1
+	// P(X >= k) 从尾部累加
+	lgN, _ := math.Lgamma(float64(n) + 1)
+	tail := 0.0
+	for k := n; k >= 0; k-- {
+		lgK, _ := math.Lgamma(float64(k) + 1)
+		lgNK, _ := math.Lgamma(float64(n-k) + 1)
+		tail += math.Exp(lgN - lgK - lgNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+		if tail > alpha {
+			// 第一个样本本身计 1
+			return k + 2
+		}
+	}
+	return 1
+}
+
+// Feed 检测一批样本, 失败后返回错误
+func (t *HealthTester) Feed(samples []byte) error {
+	if !t.status.OK {
+		return t.status.Err
+	}
+	for _, b := range samples {
+		t.status.Samples++
+
+		// 重复计数检测
+		if t.run > 0 && b == t.last {
+			t.run++
+			if t.run >= t.rctCutoff {
+				t.status.RctFailures++
+				return t.fail(fmt.Errorf("repetition count test failed: %d identical samples", t.run))
+			}
+		} else {
+			t.last = b
+			t.run = 1
+		}
+
+		// 自适应比例检测
+		if t.aptIdx == 0 {
+			t.aptRef = b
+			t.aptCount = 1
+		} else if b == t.aptRef {
+			t.aptCount++
+			if t.aptCount >= t.aptCutoff {
+				t.status.AptFailures++
+				return t.fail(fmt.Errorf("adaptive proportion test failed: %d of %d samples identical", t.aptCount, AptWindow))
+			}
+		}
+		t.aptIdx = (t.aptIdx + 1) % AptWindow
+	}
+	return nil
+}
+
+func (t *HealthTester) fail(err error) error {
+	t.status.OK = false
+	t.status.Err = err
+	t.status.FailedAt = time.Now()
+	return err
+}
+
+// Reset 清除失败状态, 从新的样本重新开始检测; 累计的样本数和失败次数保留
+// 之后需要重新通过开机检测才能输出
+func (t *HealthTester) Reset() {
+	t.run, t.aptIdx, t.aptCount = 0, 0, 0
+	t.status.OK, t.status.Err, t.status.FailedAt = true, nil, time.Time{}
+}
+
+// Status 当前检测状态
+func (t *HealthTester) Status() HealthStatus {
+	return t.status
+}
+
+// entropySource 经过健康检测的 crypto/rand, 检测失败或读取失败时拒绝输出,
+// 到 next 时自动重新执行开机检测, 通过后恢复输出
+type entropySource struct {
+	mu     sync.Mutex
+	read   func([]byte) (int, error)
+	now    func() time.Time
+	health *HealthTester
+
+	backoff   time.Duration // 下一次失败后的等待时间
+	next      time.Time     // 下一次自动重新检测的时间
+	recovered time.Time     // 最近一次通过重新检测的时间
+	retests   uint64
+}
+
+func newEntropySource() *entropySource {
+	e := &entropySource{
+		read:    crand.Read,
+		now:     time.Now,
+		health:  NewHealthTester(EntropyPerSample),
+		backoff: retestBackoff,
+	}
+	// 开机检测, 失败时锁定, 之后的请求全部拒绝
+	e.mu.Lock()
+	e.startup()
+	e.mu.Unlock()
+	return e
+}
+
+// startup 开机检测 startupSamples 个样本, 调用方持有 e.mu
+func (e *entropySource) startup() error {
+	return e.fillLocked(make([]byte, startupSamples))
+}
+
+// retest 运维触发的重新检测: 清除失败状态并重新执行开机检测, 通过后恢复输出
+// 检测期间不输出, 检测失败时保持锁定; 自动重新检测的间隔从头开始
+func (e *entropySource) retest() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.backoff = retestBackoff
+	e.health.Reset()
+	return e.startup()
+}
+
+// autoRetest 失败状态下到时间后自动重新检测, 调用方持有 e.mu
+func (e *entropySource) autoRetest() {
+	if e.health.Status().OK || e.now().Before(e.next) {
+		return
+	}
+	e.retests++
+	e.health.Reset()
+	if e.startup() == nil {
+		e.recovered = e.now()
+	}
+}
+
+// failed 记录失败并安排下一次自动重新检测, 调用方持有 e.mu
+// 恢复后很快又失败说明不是偶然的误报, 间隔倍增
+func (e *entropySource) failed() {
+	now := e.now()
+	if !e.recovered.IsZero() && now.Sub(e.recovered) > maxRetestBackoff {
+		e.backoff = retestBackoff
+	}
+	e.next = now.Add(e.backoff)
+	e.backoff = min(2*e.backoff, maxRetestBackoff)
+}
+
+// fill 用经过检测的熵填满 buf
+func (e *entropySource) fill(buf []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.fillLocked(buf)
+}
+
+func (e *entropySource) fillLocked(buf []byte) error {
+	e.autoRetest()
+	if st := e.health.Status(); !st.OK {
+		return st.Err
+	}
+	if _, err := e.read(buf); err != nil {
+		e.failed()
+		return e.health.fail(fmt.Errorf("entropy source read failed: %v", err))
+	}
+	if err := e.health.Feed(buf); err != nil {
+		e.failed()
+		return err
+	}
+	return nil
+}
+
+// uint32s 返回 n 个 32 位随机数
+func (e *entropySource) uint32s(n int) ([]uint32, error) {
+	buf := make([]byte, 4*n)
+	if err := e.fill(buf); err != nil {
+		return nil, err
+	}
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = binary.LittleEndian.Uint32(buf[4*i:])
+	}
+	return out, nil
+}
+
+// status 返回检测状态; 定期调用(watchHealth)使没有请求时也能按时自动重新检测
+func (e *entropySource) status() HealthStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.autoRetest()
+	st := e.health.Status()
+	st.Retests = e.retests
+	if !st.OK {
+		st.NextRetest = e.next
+	}
+	return st
+}
@@ -0,0 +1,83 @@
+package rng
+
+import (
+	crand "crypto/rand"
+	"testing"
+	"time"
+)
+
+// TestAutoRetest 检查失败后按退避间隔自动重新检测, 通过后恢复输出
+func TestAutoRetest(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stuck := false
+	e := &entropySource{
+		read: func(b []byte) (int, error) {
+			if stuck {
+				clear(b)
+				return len(b), nil
+			}
+			return crand.Read(b)
+		},
+		now:     func() time.Time { return now },
+		health:  NewHealthTester(EntropyPerSample),
+		backoff: retestBackoff,
+	}
+	buf := make([]byte, 64)
+	if err := e.fill(buf); err != nil {
+		t.Fatalf("fill() error = %v", err)
+	}
+
+	stuck = true
+	if err := e.fill(buf); err == nil {
+		t.Fatal("fill(stuck) error = nil")
+	}
+	st := e.status()
+	if st.OK || !st.NextRetest.Equal(now.Add(retestBackoff)) {
+		t.Fatalf("status() ok = %v, nextRetest = %v", st.OK, st.NextRetest)
+	}
+
+	// 熵源仍然故障: 重新检测失败, 间隔倍增
+	now = now.Add(retestBackoff)
+	if st := e.status(); st.OK || st.Retests != 1 || !st.NextRetest.Equal(now.Add(2*retestBackoff)) {
+		t.Fatalf("status() after failed retest = %+v", st)
+	}
+	// 没到时间不重新检测
+	stuck = false
+	now = now.Add(retestBackoff)
+	if err := e.fill(buf); err == nil || e.retests != 1 {
+		t.Fatalf("fill() before retest error = %v, retests = %d", err, e.retests)
+	}
+
+	// 到时间后自动重新检测并输出
+	now = now.Add(retestBackoff)
+	if err := e.fill(buf); err != nil {
+		t.Fatalf("fill() after retest error = %v", err)
+	}
+	if st := e.status(); !st.OK || st.Retests != 2 || !st.NextRetest.IsZero() || st.RctFailures != 2 {
+		t.Errorf("status() after recovery = %+v", st)
+	}
+
+	// 恢复后很快又失败, 间隔继续倍增; 长时间运行后再失败, 间隔从头开始
+	stuck = true
+	e.fill(buf)
+	if st := e.status(); !st.NextRetest.Equal(now.Add(4 * retestBackoff)) {
+		t.Errorf("nextRetest after quick failure = %v, want %v", st.NextRetest, now.Add(4*retestBackoff))
+	}
+	stuck = false
+	now = now.Add(4 * retestBackoff)
+	if st := e.status(); !st.OK {
+		t.Fatalf("status() after second recovery = %+v", st)
+	}
+	now = now.Add(2 * maxRetestBackoff)
+	stuck = true
+	e.fill(buf)
+	if st := e.status(); !st.NextRetest.Equal(now.Add(retestBackoff)) {
+		t.Errorf("nextRetest after long run = %v, want %v", st.NextRetest, now.Add(retestBackoff))
+	}
+
+	// 运维重置同样从头开始
+	stuck = false
+	if err := e.retest(); err != nil || e.backoff != retestBackoff {
+		t.Errorf("retest() error = %v, backoff = %v", err, e.backoff)
+	}
+}
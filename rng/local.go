@@ -0,0 +1,75 @@
+package rng
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"google.golang.org/grpc"
+)
+
+// localRng 进程内RNG, 以 proto.RngClient 的形式直接调用 Rng
+type localRng struct {
+	srv *Rng
+}
+
+func (l localRng) GetRngs(ctx context.Context, in *proto.RequestRngs, opts ...grpc.CallOption) (*proto.ReplyRngs, error) {
+	return l.srv.GetRngs(ctx, in)
+}
+
+func (l localRng) StreamRngs(ctx context.Context, in *proto.RequestRngs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.ReplyRngs], error) {
+	st := &localStream{ctx: ctx}
+	if err := l.srv.StreamRngs(in, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// localStream 进程内流, 服务端一次写完, 客户端按顺序读出
+type localStream struct {
+	grpc.ServerStream // 不会被调用, 只为满足接口
+	grpc.ClientStream
+	ctx     context.Context
+	replies []*proto.ReplyRngs
+}
+
+func (s *localStream) Context() context.Context { return s.ctx }
+
+func (s *localStream) SendMsg(m any) error { return s.Send(m.(*proto.ReplyRngs)) }
+func (s *localStream) RecvMsg(m any) error { return errors.New("localStream: use Recv") }
+
+func (s *localStream) Send(r *proto.ReplyRngs) error {
+	s.replies = append(s.replies, r)
+	return nil
+}
+
+func (s *localStream) Recv() (*proto.ReplyRngs, error) {
+	if len(s.replies) == 0 {
+		return nil, io.EOF
+	}
+	r := s.replies[0]
+	s.replies = s.replies[1:]
+	return r, nil
+}
+
+func (l localRng) Health(ctx context.Context, in *proto.RequestHealth, opts ...grpc.CallOption) (*proto.ReplyHealth, error) {
+	return l.srv.Health(ctx, in)
+}
+
+// NewLocalRNGClient 创建使用进程内RNG的客户端, 不需要单独的RNG服务
+func NewLocalRNGClient() (*RNGClient, error) {
+	return NewLocalRNGClientWithConfig(Config{})
+}
+
+// NewLocalRNGClientWithConfig 创建按配置选择发生器的进程内RNG客户端
+func NewLocalRNGClientWithConfig(cfg Config) (*RNGClient, error) {
+	srv, err := NewRngWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RNGClient{
+		client:   localRng{srv: srv},
+		gamecode: GameCode,
+	}, nil
+}
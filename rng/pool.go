@@ -0,0 +1,143 @@
+package rng
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// 默认预取参数
+const (
+	DefaultPoolLow     = 256
+	DefaultPoolHigh    = 4096
+	DefaultCallTimeout = 2 * time.Second
+)
+
+// refillBackoff 补充失败后的重试间隔
+const refillBackoff = 200 * time.Millisecond
+
+// PoolConfig 客户端本地随机数池配置
+type PoolConfig struct {
+	Low     int           // 池中少于 Low 个时后台补充
+	High    int           // 每次补充到 High 个, 0 表示不使用池
+	Timeout time.Duration // 每次 GetRngs 调用的超时, 0 为默认值
+}
+
+// PoolStats 随机数池指标
+type PoolStats struct {
+	Size         int    // 当前池中的原始随机数
+	Hits         uint64 // 直接从池中取到的请求
+	Misses       uint64 // 池不够, 同步请求RNG服务的请求
+	Refills      uint64 // 后台补充次数
+	RefillErrors uint64 // 后台补充失败次数
+}
+
+// pool 后台补充的原始 32 位随机数池, 并发安全
+type pool struct {
+	mu    sync.Mutex
+	buf   []uint32
+	low   int
+	high  int
+	stats PoolStats
+
+	fetch  func(n int) ([]uint32, error)
+	refill chan struct{}
+	done   chan struct{}
+}
+
+func newPool(cfg PoolConfig, fetch func(n int) ([]uint32, error)) *pool {
+	if cfg.Low <= 0 {
+		cfg.Low = DefaultPoolLow
+	}
+	if cfg.Low > cfg.High {
+		cfg.Low = cfg.High
+	}
+	p := &pool{
+		low:    cfg.Low,
+		high:   cfg.High,
+		fetch:  fetch,
+		refill: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	p.kick()
+	return p
+}
+
+// kick 通知后台补充, 不阻塞
+func (p *pool) kick() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (p *pool) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.refill:
+		}
+		for {
+			p.mu.Lock()
+			need := p.high - len(p.buf)
+			p.mu.Unlock()
+			if need < p.high-p.low || need <= 0 {
+				break
+			}
+
+			vals, err := p.fetch(need)
+			p.mu.Lock()
+			if err != nil {
+				p.stats.RefillErrors++
+				p.mu.Unlock()
+				log.Warn().Err(err).Msg("RNG pool refill failed")
+				select {
+				case <-p.done:
+					return
+				case <-time.After(refillBackoff):
+				}
+				continue
+			}
+			p.buf = append(p.buf, vals...)
+			p.stats.Refills++
+			p.mu.Unlock()
+		}
+	}
+}
+
+// take 取出 n 个原始随机数, 池不够时同步请求
+func (p *pool) take(n int) ([]uint32, error) {
+	p.mu.Lock()
+	if len(p.buf) >= n {
+		out := make([]uint32, n)
+		copy(out, p.buf)
+		p.buf = p.buf[n:]
+		p.stats.Hits++
+		low := len(p.buf) < p.low
+		p.mu.Unlock()
+		if low {
+			p.kick()
+		}
+		return out, nil
+	}
+	p.stats.Misses++
+	p.mu.Unlock()
+
+	p.kick()
+	return p.fetch(n)
+}
+
+func (p *pool) snapshot() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := p.stats
+	st.Size = len(p.buf)
+	return st
+}
+
+func (p *pool) close() {
+	close(p.done)
+}
@@ -2,62 +2,251 @@ package rng
 
 import (
 	"context"
-	crand "crypto/rand"
 	"fmt"
-	"math/big"
-	"math/rand"
 	"net"
 	"time"
 
 	"gitee.com/heartfun/rouletteserv/proto"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// Config RNG服务配置
+type Config struct {
+	Port           string
+	Algorithm      string        // 发生器算法, 见 Algorithms, 默认 crypto
+	ReseedInterval uint64        // 确定性发生器每隔多少次生成请求重新播种, 0 为默认值
+	ReseedPeriod   time.Duration // 确定性发生器重新播种的最长时间间隔, 0 为默认值
+	StreamIdle     time.Duration // 流数量达到上限时回收空闲超过此时间的流, 0 为默认值
+	AdminPort      string        // 运维端口, 提供 RngAdmin 服务; 为空时不提供
+}
+
 // Rng RNG服务实现
+//
+// 每个游戏代码和桌子使用独立的发生器实例, 互不影响.
 type Rng struct {
 	proto.UnimplementedRngServer
+	src     *entropySource
+	alg     string
+	streams *streams
 }
 
-// NewRng 创建新的RNG服务
-func NewRng() *Rng {
-	return &Rng{}
+// NewRng 创建使用 crypto/rand 的RNG服务, 熵源开机健康检测失败时返回错误
+func NewRng() (*Rng, error) {
+	return NewRngWithConfig(Config{Algorithm: AlgCrypto})
 }
 
+// NewRngWithConfig 按配置选择发生器创建RNG服务
+func NewRngWithConfig(cfg Config) (*Rng, error) {
+	src := newEntropySource()
+	if st := src.status(); !st.OK {
+		return nil, fmt.Errorf("entropy source failed start-up health tests: %v", st.Err)
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgCrypto
+	}
+	s := &Rng{
+		src:     src,
+		alg:     cfg.Algorithm,
+		streams: newStreams(src, cfg),
+	}
+	// 先创建默认流, 顺便检查算法配置
+	if _, err := s.streams.get("", ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RngBits 每个随机数的位数
+const RngBits = 32
+
+// GetRngs 返回 nums 个随机数
+// req.Range 为 0 时返回完整的 32 位随机数, 否则在服务端无偏缩放到 [0, range)
 func (s *Rng) GetRngs(ctx context.Context, req *proto.RequestRngs) (*proto.ReplyRngs, error) {
-		Max := 36
-		Min := 0
-		nums := int(req.Nums)
-		if nums <= 0 {
-			nums = 1
-		}
-		rngs := make([]uint32, 0, nums)
-		rd := rand.New(rand.NewSource(time.Now().UnixNano()))
-		for i := 0; i < nums; i++ {
-			n, err := crand.Int(crand.Reader, big.NewInt(int64(Max+1)))
-			if err == nil {
-				rngs = append(rngs, uint32(n.Int64()))
-			} else {
-				log.Err(err).Msg("failed to get random number from crypto/rand, using math/rand fallback")
-				num := rd.Intn(int(Max-Min+1)) + int(Min)
-				rngs = append(rngs, uint32(num))
+	nums := int(req.Nums)
+	if nums <= 0 {
+		nums = 1
+	}
+	if req.Range < 0 {
+		return nil, fmt.Errorf("invalid range %d", req.Range)
+	}
+
+	st, err := s.streams.get(req.Gamecode, req.Table)
+	if err != nil {
+		log.Err(err).Str("gamecode", req.Gamecode).Str("table", req.Table).Msg("no RNG stream")
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	rngs := make([]uint32, 0, nums)
+	rejected := 0
+	for len(rngs) < nums {
+		// 熵源健康检测失败时拒绝服务, 不回退到其它随机数来源
+		vals, err := readUint32s(st.gen, nums-len(rngs))
+		if err != nil {
+			log.Err(err).Msg("entropy source unhealthy, refusing to serve")
+			return nil, status.Error(codes.Unavailable, "entropy source unhealthy")
+		}
+		for _, v := range vals {
+			if req.Range > 0 {
+				scaled, ok := ScaleUniform(v, uint32(req.Range))
+				if !ok {
+					rejected++
+					continue
+				}
+				v = scaled
 			}
+			rngs = append(rngs, v)
 		}
-		return &proto.ReplyRngs{
-			Rngs: rngs,
-		}, nil
+	}
+	st.record(req.Range, rngs, rejected)
+
+	return &proto.ReplyRngs{
+		Rngs:      rngs,
+		Bits:      RngBits,
+		Range:     req.Range,
+		Algorithm: st.gen.Name(),
+	}, nil
+}
+
+// 流式请求每次应答的默认和最大数量
+const (
+	DefaultStreamChunk = 4096
+	maxStreamChunk     = 65536
+)
+
+// StreamRngs 分块流式返回 req.Nums 个随机数, 发送受 gRPC 流控限制
+func (s *Rng) StreamRngs(req *proto.RequestRngs, stream grpc.ServerStreamingServer[proto.ReplyRngs]) error {
+	chunk := int32(DefaultStreamChunk)
+	if req.Chunk > 0 {
+		chunk = min(req.Chunk, maxStreamChunk)
+	}
+	ctx := stream.Context()
+	for remaining := max(req.Nums, 1); remaining > 0; {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		n := min(chunk, remaining)
+		reply, err := s.GetRngs(ctx, &proto.RequestRngs{
+			Nums:     n,
+			Gamecode: req.Gamecode,
+			Range:    req.Range,
+			Table:    req.Table,
+		})
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(reply); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+	return nil
+}
+
+// Health 返回熵源健康检测状态
+func (s *Rng) Health(ctx context.Context, req *proto.RequestHealth) (*proto.ReplyHealth, error) {
+	st := s.src.status()
+	reply := &proto.ReplyHealth{
+		Ok:               st.OK,
+		Samples:          st.Samples,
+		RctFailures:      st.RctFailures,
+		AptFailures:      st.AptFailures,
+		RctCutoff:        int32(st.RctCutoff),
+		AptCutoff:        int32(st.AptCutoff),
+		AptWindow:        AptWindow,
+		EntropyPerSample: EntropyPerSample,
+		Algorithm:        s.alg,
+		Reseeds:          s.streams.reseeds(),
+		Retests:          st.Retests,
+	}
+	if st.Err != nil {
+		reply.Error = st.Err.Error()
+		reply.FailedAt = st.FailedAt.Unix()
+	}
+	if !st.NextRetest.IsZero() {
+		reply.NextRetest = st.NextRetest.Unix()
+	}
+	return reply, nil
+}
+
+// ScaleUniform 把 32 位随机数无偏缩放到 [0, r)
+// 落在最后一个不完整区间的值会被拒绝, 返回 false, 调用方需要重新取数
+func ScaleUniform(v uint32, r uint32) (uint32, bool) {
+	const maxRange = uint64(1) << RngBits
+	limit := maxRange - maxRange%uint64(r)
+	if uint64(v) >= limit {
+		return 0, false
+	}
+	return v % r, true
 }
 
 // StartServer 启动RNG服务
-func StartServer(port string) error {
-	lis, err := net.Listen("tcp", ":"+port)
+func StartServer(cfg Config) error {
+	srv, err := NewRngWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", ":"+cfg.Port)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
 	grpcServer := grpc.NewServer()
-	proto.RegisterRngServer(grpcServer, NewRng())
+	proto.RegisterRngServer(grpcServer, srv)
+
+	// 管理服务不放在对外端口上
+	if cfg.AdminPort != "" {
+		adminLis, err := net.Listen("tcp", ":"+cfg.AdminPort)
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("failed to listen on admin port: %v", err)
+		}
+		adminServer := grpc.NewServer()
+		proto.RegisterRngAdminServer(adminServer, NewAdmin(srv))
+		go func() {
+			if err := adminServer.Serve(adminLis); err != nil {
+				log.Err(err).Msg("RNG admin server stopped")
+			}
+		}()
+		defer adminServer.Stop()
+		log.Info().Msg("Starting RNG admin server on port " + cfg.AdminPort)
+	}
+
+	// 标准 gRPC 健康检查, 熵源失败后变为 NOT_SERVING
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, hs)
+	go watchHealth(srv, hs)
 
-	log.Info().Msg("Starting RNG server on port " + port)
+	log.Info().Str("algorithm", srv.alg).Msg("Starting RNG server on port " + cfg.Port)
 	return grpcServer.Serve(lis)
 }
+
+// watchHealth 把熵源状态同步到 gRPC 健康检查, 管理服务重置并通过检测后恢复 SERVING
+func watchHealth(srv *Rng, hs *health.Server) {
+	serving := healthpb.HealthCheckResponse_SERVING
+	hs.SetServingStatus("", serving)
+	hs.SetServingStatus("sgc7pb.Rng", serving)
+	for range time.Tick(time.Second) {
+		st := srv.src.status()
+		want := healthpb.HealthCheckResponse_SERVING
+		if !st.OK {
+			want = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if want == serving {
+			continue
+		}
+		serving = want
+		if st.OK {
+			log.Warn().Msg("RNG entropy source passed health tests again, serving")
+		} else {
+			log.Error().Err(st.Err).Msg("RNG entropy source failed health tests, not serving")
+		}
+		hs.SetServingStatus("", serving)
+		hs.SetServingStatus("sgc7pb.Rng", serving)
+	}
+}
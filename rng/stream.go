@@ -0,0 +1,266 @@
+package rng
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+)
+
+// maxStreams 最多同时维护的流数量, 防止任意游戏代码耗尽内存
+const maxStreams = 1024
+
+// DefaultStreamIdle 流的默认空闲回收时间, 达到 maxStreams 时回收空闲超过此时间的流
+const DefaultStreamIdle = 30 * time.Minute
+
+// maxStatsRange 不超过此范围的缩放输出按值分桶统计
+const maxStatsRange = 1024
+
+// rawBuckets 原始 32 位输出按最高字节分桶
+const rawBuckets = 256
+
+// streamKey 每个游戏代码和桌子对应一个独立的流
+type streamKey struct {
+	gamecode string
+	table    string
+}
+
+// rangeStats 某个范围的输出分布
+type rangeStats struct {
+	counts []uint64
+	total  uint64
+}
+
+// stream 独立的发生器实例和它的统计
+type stream struct {
+	key streamKey
+	gen Generator
+
+	mu       sync.Mutex
+	created  time.Time
+	lastUsed time.Time
+	requests uint64
+	values   uint64
+	rejected uint64
+	ranges   map[int32]*rangeStats
+}
+
+// streams 按需创建流, 每个流单独从系统熵源播种
+type streams struct {
+	mu  sync.Mutex
+	src *entropySource
+	cfg Config
+	m   map[streamKey]*stream
+}
+
+func newStreams(src *entropySource, cfg Config) *streams {
+	if cfg.StreamIdle <= 0 {
+		cfg.StreamIdle = DefaultStreamIdle
+	}
+	return &streams{
+		src: src,
+		cfg: cfg,
+		m:   make(map[streamKey]*stream),
+	}
+}
+
+// get 返回 gamecode/table 对应的流, 不存在时创建
+func (ss *streams) get(gamecode, table string) (*stream, error) {
+	key := streamKey{gamecode: gamecode, table: table}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if st, ok := ss.m[key]; ok {
+		return st, nil
+	}
+	if len(ss.m) >= maxStreams {
+		ss.evictIdle(time.Now().Add(-ss.cfg.StreamIdle))
+	}
+	if len(ss.m) >= maxStreams {
+		return nil, fmt.Errorf("too many RNG streams (%d)", maxStreams)
+	}
+	gen, err := NewGenerator(ss.cfg.Algorithm, ss.src, ss.cfg.ReseedInterval, ss.cfg.ReseedPeriod)
+	if err != nil {
+		return nil, err
+	}
+	st := &stream{
+		key:     key,
+		gen:     gen,
+		created: time.Now(),
+		ranges:  make(map[int32]*rangeStats),
+	}
+	ss.m[key] = st
+	return st, nil
+}
+
+// evictIdle 回收 since 之后没有使用过的流, 调用方持有 ss.mu
+// 正在使用被回收的流的请求不受影响, 下一次请求创建新的流
+func (ss *streams) evictIdle(since time.Time) {
+	n := 0
+	for key, st := range ss.m {
+		st.mu.Lock()
+		last := st.lastUsed
+		if last.IsZero() {
+			last = st.created
+		}
+		st.mu.Unlock()
+		if last.Before(since) {
+			delete(ss.m, key)
+			n++
+		}
+	}
+	if n > 0 {
+		log.Info().Int("evicted", n).Int("streams", len(ss.m)).Msg("evicted idle RNG streams")
+	}
+}
+
+// all 按游戏代码和桌子排序返回所有流
+func (ss *streams) all() []*stream {
+	ss.mu.Lock()
+	out := make([]*stream, 0, len(ss.m))
+	for _, st := range ss.m {
+		out = append(out, st)
+	}
+	ss.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].key.gamecode != out[j].key.gamecode {
+			return out[i].key.gamecode < out[j].key.gamecode
+		}
+		return out[i].key.table < out[j].key.table
+	})
+	return out
+}
+
+// reseeds 所有流重新播种次数之和
+func (ss *streams) reseeds() uint64 {
+	var n uint64
+	for _, st := range ss.all() {
+		n += st.gen.Reseeds()
+	}
+	return n
+}
+
+// record 记录一次请求的输出
+func (st *stream) record(r int32, vals []uint32, rejected int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.lastUsed = time.Now()
+	st.requests++
+	st.values += uint64(len(vals))
+	st.rejected += uint64(rejected)
+
+	if r > maxStatsRange {
+		return
+	}
+	rs := st.ranges[r]
+	if rs == nil {
+		n := int(r)
+		if r == 0 {
+			n = rawBuckets
+		}
+		rs = &rangeStats{counts: make([]uint64, n)}
+		st.ranges[r] = rs
+	}
+	for _, v := range vals {
+		if r == 0 {
+			v >>= 24
+		}
+		rs.counts[v]++
+	}
+	rs.total += uint64(len(vals))
+}
+
+// stats 转成应答, counts 为 false 时不带分桶计数
+func (st *stream) stats(counts bool) *proto.StreamStats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	ps := &proto.StreamStats{
+		Gamecode:  st.key.gamecode,
+		Table:     st.key.table,
+		Algorithm: st.gen.Name(),
+		Requests:  st.requests,
+		Values:    st.values,
+		Rejected:  st.rejected,
+		Reseeds:   st.gen.Reseeds(),
+		Created:   st.created.Unix(),
+	}
+	if !st.lastUsed.IsZero() {
+		ps.LastUsed = st.lastUsed.Unix()
+	}
+
+	rs := make([]int32, 0, len(st.ranges))
+	for r := range st.ranges {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i] < rs[j] })
+	for _, r := range rs {
+		s := st.ranges[r]
+		pr := &proto.RangeStats{
+			Range:     r,
+			Total:     s.total,
+			ChiSquare: chiSquare(s.counts, s.total),
+			Df:        int32(len(s.counts) - 1),
+		}
+		if counts {
+			pr.Counts = append([]uint64(nil), s.counts...)
+		}
+		ps.Ranges = append(ps.Ranges, pr)
+	}
+	return ps
+}
+
+// chiSquare 分桶计数相对均匀分布的卡方统计量
+func chiSquare(counts []uint64, total uint64) float64 {
+	if total == 0 || len(counts) == 0 {
+		return 0
+	}
+	expected := float64(total) / float64(len(counts))
+	var x float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		x += d * d / expected
+	}
+	return x
+}
+
+// Admin RNG管理服务, 只应暴露给运维
+type Admin struct {
+	proto.UnimplementedRngAdminServer
+	rng *Rng
+}
+
+// NewAdmin 创建 s 的管理服务
+func NewAdmin(s *Rng) *Admin {
+	return &Admin{rng: s}
+}
+
+// ResetHealth 清除熵源的失败状态并重新执行开机检测
+// 熵源失败后会按退避间隔自动重新检测; 运维排除故障后可以用它立即重新检测, 检测失败时继续拒绝服务
+func (a *Admin) ResetHealth(ctx context.Context, req *proto.RequestResetHealth) (*proto.ReplyHealth, error) {
+	prev := a.rng.src.status()
+	if err := a.rng.src.retest(); err != nil {
+		log.Err(err).Msg("RNG entropy source failed health tests after reset")
+	} else {
+		log.Warn().AnErr("previous", prev.Err).Msg("RNG entropy source health reset")
+	}
+	return a.rng.Health(ctx, &proto.RequestHealth{})
+}
+
+// Streams 返回每个流的计数和输出统计
+func (a *Admin) Streams(ctx context.Context, req *proto.RequestStreams) (*proto.ReplyStreams, error) {
+	reply := &proto.ReplyStreams{}
+	for _, st := range a.rng.streams.all() {
+		if req.Gamecode != "" && st.key.gamecode != req.Gamecode {
+			continue
+		}
+		reply.Streams = append(reply.Streams, st.stats(req.Counts))
+	}
+	return reply, nil
+}
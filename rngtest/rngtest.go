@@ -0,0 +1,178 @@
+package rngtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+	"github.com/rs/zerolog/log"
+)
+
+// fetchChunk keeps each RNG call well under the gRPC message limit.
+const fetchChunk = 65536
+
+// Config configures one run of the statistical test suite.
+type Config struct {
+	RngAddr string     // RNG service address, empty for the in-process RNG
+	Samples int        // values per sample
+	Ranges  []int      // scaled samples to test besides the raw output
+	Alpha   float64    // two-sided significance level of the whole suite
+	Report  string     // report path prefix, writes <prefix>.json and <prefix>.txt
+	Rng     rng.Config // generator of the in-process RNG, ignored with RngAddr
+}
+
+// Report is the outcome of the whole suite.
+type Report struct {
+	Source    string    `json:"source"`
+	Samples   int       `json:"samples"`
+	Alpha     float64   `json:"alpha"`
+	TestAlpha float64   `json:"testAlpha"` // Šidák corrected level of each test
+	Time      time.Time `json:"time"`
+	Pass      bool      `json:"pass"`
+	Results   []Result  `json:"results"`
+}
+
+// tests is every statistical test run on each sample.
+var tests = []func(*sample) Result{
+	chiSquareUniformity,
+	serialCorrelation,
+	runs,
+	gap,
+	poker,
+	birthdaySpacings,
+}
+
+// Run pulls the samples, runs every test and writes the report.
+func Run(cfg Config) (*Report, error) {
+	if cfg.Samples <= 0 {
+		return nil, fmt.Errorf("invalid sample size %d", cfg.Samples)
+	}
+	if cfg.Alpha <= 0 || cfg.Alpha >= 1 {
+		return nil, fmt.Errorf("invalid significance level %g", cfg.Alpha)
+	}
+	var client *rng.RNGClient
+	source := cfg.RngAddr
+	if source != "" {
+		c, err := rng.NewRNGClient(cfg.RngAddr)
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	} else {
+		c, err := rng.NewLocalRNGClientWithConfig(cfg.Rng)
+		if err != nil {
+			return nil, err
+		}
+		client = c
+		source = "in-process"
+		if cfg.Rng.Algorithm != "" {
+			source += " " + cfg.Rng.Algorithm
+		}
+	}
+	defer client.Close()
+	// keep certification draws out of the game streams' statistics
+	client = client.Stream("rngtest", "")
+
+	samples := []*sample{{name: "raw32", span: 1 << 32}}
+	for _, r := range cfg.Ranges {
+		if r < 2 {
+			return nil, fmt.Errorf("invalid range %d", r)
+		}
+		samples = append(samples, &sample{name: fmt.Sprintf("range%d", r), span: uint64(r)})
+	}
+
+	rep := &Report{
+		Source:  source,
+		Samples: cfg.Samples,
+		Alpha:   cfg.Alpha,
+		Time:    time.Now(),
+	}
+	for _, s := range samples {
+		start := time.Now()
+		vals, err := pull(client, s.span, cfg.Samples)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample %s: %v", s.name, err)
+		}
+		s.vals = vals
+		log.Info().Str("sample", s.name).Int("values", len(vals)).Dur("took", time.Since(start)).Msg("sample pulled")
+
+		for _, t := range tests {
+			rep.Results = append(rep.Results, t(s))
+		}
+	}
+	rep.TestAlpha, rep.Pass = judgeAll(rep.Results, cfg.Alpha)
+
+	if cfg.Report != "" {
+		j, _ := json.MarshalIndent(rep, "", "  ")
+		if err := os.WriteFile(cfg.Report+".json", j, 0o644); err != nil {
+			return rep, err
+		}
+		f, err := os.Create(cfg.Report + ".txt")
+		if err != nil {
+			return rep, err
+		}
+		defer f.Close()
+		rep.WriteText(f)
+	}
+	return rep, nil
+}
+
+// pull draws n values; raw values for span 2^32, else client-scaled values.
+func pull(client *rng.RNGClient, span uint64, n int) ([]uint32, error) {
+	out := make([]uint32, 0, n)
+	for len(out) < n {
+		chunk := n - len(out)
+		if chunk > fetchChunk {
+			chunk = fetchChunk
+		}
+		if span == 1<<32 {
+			vals, err := client.GetRawNumbers(int32(chunk))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, vals...)
+			continue
+		}
+		vals, err := client.GetRandomNumbers(int32(chunk), int(span))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vals {
+			out = append(out, v%uint32(span))
+		}
+	}
+	return out[:n], nil
+}
+
+// WriteText writes a human readable report.
+func (r *Report) WriteText(w io.Writer) {
+	verdict := "PASS"
+	if !r.Pass {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(w, "RNG statistical test report  %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(w, "source: %s  samples: %d  alpha: %g (two-sided, whole suite)\n", r.Source, r.Samples, r.Alpha)
+	fmt.Fprintf(w, "each test judged at %.6g (Šidák correction)\n", r.TestAlpha)
+	fmt.Fprintln(w, strings.Repeat("-", 88))
+	fmt.Fprintf(w, "%-12s %-22s %14s %5s %12s  %s\n", "sample", "test", "statistic", "df", "p-value", "result")
+	for _, res := range r.Results {
+		status := "pass"
+		switch {
+		case res.Skipped:
+			status = "skipped: " + res.Note
+		case !res.Pass:
+			status = "FAIL"
+		}
+		df := ""
+		if res.DF > 0 {
+			df = fmt.Sprint(res.DF)
+		}
+		fmt.Fprintf(w, "%-12s %-22s %14.6g %5s %12.6f  %s\n", res.Sample, res.Test, res.Statistic, df, res.PValue, status)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 88))
+	fmt.Fprintf(w, "overall: %s\n", verdict)
+}
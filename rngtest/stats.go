@@ -0,0 +1,116 @@
+package rngtest
+
+import "math"
+
+// minExpected is the smallest expected bin count a chi-square bin may have;
+// smaller neighbouring bins are merged.
+const minExpected = 5
+
+// chiSquare returns the statistic and degrees of freedom after merging
+// adjacent bins whose expected count is below minExpected.
+func chiSquare(obs, exp []float64) (float64, int) {
+	var mo, me []float64
+	accO, accE := 0.0, 0.0
+	for i := range obs {
+		accO += obs[i]
+		accE += exp[i]
+		if accE >= minExpected {
+			mo = append(mo, accO)
+			me = append(me, accE)
+			accO, accE = 0, 0
+		}
+	}
+	if accE > 0 {
+		if len(me) == 0 {
+			mo = append(mo, accO)
+			me = append(me, accE)
+		} else {
+			mo[len(mo)-1] += accO
+			me[len(me)-1] += accE
+		}
+	}
+
+	stat := 0.0
+	for i := range mo {
+		d := mo[i] - me[i]
+		stat += d * d / me[i]
+	}
+	return stat, len(mo) - 1
+}
+
+// chiSquareP is the upper tail probability of the chi-square distribution.
+func chiSquareP(stat float64, df int) float64 {
+	if df <= 0 {
+		return 1
+	}
+	return gammaQ(float64(df)/2, stat/2)
+}
+
+// normalP is the two-sided tail probability of a standard normal z.
+func normalP(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x).
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - gammaPSeries(a, x)
+	}
+	return gammaQFraction(a, x)
+}
+
+// gammaPSeries evaluates P(a, x) by its series, good for x < a+1.
+func gammaPSeries(a, x float64) float64 {
+	lg, _ := math.Lgamma(a)
+	ap := a
+	sum := 1 / a
+	del := sum
+	for n := 0; n < 10000; n++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*1e-15 {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lg)
+}
+
+// gammaQFraction evaluates Q(a, x) by Lentz's continued fraction, good for
+// x >= a+1.
+func gammaQFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	lg, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 10000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
+
+// poisson returns P(X = k) for X ~ Poisson(lambda).
+func poisson(lambda float64, k int) float64 {
+	lg, _ := math.Lgamma(float64(k) + 1)
+	return math.Exp(-lambda + float64(k)*math.Log(lambda) - lg)
+}
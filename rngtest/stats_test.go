@@ -0,0 +1,135 @@
+package rngtest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGammaQ(t *testing.T) {
+	tests := []struct {
+		a, x, want float64
+	}{
+		{1, 0, 1},
+		{1, 1, math.Exp(-1)},
+		{1, 10, math.Exp(-10)},
+		{0.5, 0.25, math.Erfc(0.5)},
+		{0.5, 4, math.Erfc(2)},
+		{3, 2, 5 * math.Exp(-2)}, // Q(3, x) = e^-x (1 + x + x^2/2)
+		{3, 10, 61 * math.Exp(-10)},
+	}
+	for _, tt := range tests {
+		if got := gammaQ(tt.a, tt.x); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("gammaQ(%v, %v) = %.15g, want %.15g", tt.a, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquareP(t *testing.T) {
+	// upper 5% and 1% critical values from the chi-square table
+	tests := []struct {
+		stat float64
+		df   int
+		want float64
+	}{
+		{3.841459, 1, 0.05},
+		{6.634897, 1, 0.01},
+		{18.307038, 10, 0.05},
+		{124.342113, 100, 0.05},
+	}
+	for _, tt := range tests {
+		if got := chiSquareP(tt.stat, tt.df); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("chiSquareP(%v, %d) = %.8f, want %v", tt.stat, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquare(t *testing.T) {
+	tests := []struct {
+		obs, exp []float64
+		stat     float64
+		df       int
+	}{
+		{[]float64{12, 8}, []float64{10, 10}, 0.8, 1},
+		// the first three bins merge until the expectation reaches 5
+		{[]float64{3, 2, 3, 12}, []float64{2, 2, 2, 10}, 4.0/6 + 4.0/10, 1},
+		// a small tail joins the last bin
+		{[]float64{10, 3}, []float64{10, 1}, 4.0 / 11, 0},
+	}
+	for _, tt := range tests {
+		stat, df := chiSquare(tt.obs, tt.exp)
+		if math.Abs(stat-tt.stat) > 1e-12 || df != tt.df {
+			t.Errorf("chiSquare(%v, %v) = %v, %d, want %v, %d", tt.obs, tt.exp, stat, df, tt.stat, tt.df)
+		}
+	}
+}
+
+func TestJudge(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want bool
+	}{
+		{Result{PValue: 0.008, twoTailed: true}, false},
+		{Result{PValue: 0.012, twoTailed: true}, true},
+		{Result{PValue: 0.999, twoTailed: true}, true},
+		{Result{PValue: 0.004}, false},
+		{Result{PValue: 0.006}, true},
+		{Result{PValue: 0.996}, false},
+		{Result{Skipped: true, Pass: true}, true},
+	}
+	for _, tt := range tests {
+		r := tt.r
+		r.judge(0.01)
+		if r.Pass != tt.want {
+			t.Errorf("judge(%+v) pass = %v, want %v", tt.r, r.Pass, tt.want)
+		}
+	}
+}
+
+func TestSidak(t *testing.T) {
+	if got := sidak(0.01, 1); got != 0.01 {
+		t.Errorf("sidak(0.01, 1) = %v", got)
+	}
+	for _, m := range []int{2, 18, 30} {
+		level := sidak(0.01, m)
+		// m independent tests at level all pass with probability 1-alpha
+		if all := math.Pow(1-level, float64(m)); math.Abs(all-0.99) > 1e-12 {
+			t.Errorf("sidak(0.01, %d) = %v, suite passes with %v", m, level, all)
+		}
+		if level > 0.01/float64(m)*1.01 || level < 0.01/float64(m) {
+			t.Errorf("sidak(0.01, %d) = %v, not near Bonferroni %v", m, level, 0.01/float64(m))
+		}
+	}
+}
+
+func TestJudgeAll(t *testing.T) {
+	results := func(p float64) []Result {
+		rs := []Result{{Skipped: true, Note: "too few values"}}
+		for i := 0; i < 17; i++ {
+			rs = append(rs, Result{PValue: 0.5, twoTailed: true})
+		}
+		return append(rs, Result{PValue: p, twoTailed: true})
+	}
+
+	// 0.005 fails a single test at 0.01 but not 18 tests at 0.01 overall
+	rs := results(0.005)
+	level, pass := judgeAll(rs, 0.01)
+	if level != sidak(0.01, 18) || !pass {
+		t.Errorf("judgeAll(p=0.005) = %v, %v, want %v, true", level, pass, sidak(0.01, 18))
+	}
+	if rs[len(rs)-1].PValue != 0.005 {
+		t.Errorf("judgeAll changed the p-value to %v", rs[len(rs)-1].PValue)
+	}
+
+	rs = results(0.0001)
+	if _, pass := judgeAll(rs, 0.01); pass || rs[len(rs)-1].Pass || !rs[1].Pass {
+		t.Errorf("judgeAll(p=0.0001) pass = %v, results %+v", pass, rs)
+	}
+}
+
+func TestRunRejectsSamples(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if _, err := Run(Config{Samples: n, Alpha: 0.01}); err == nil {
+			t.Errorf("Run(Samples: %d) error = nil", n)
+		}
+	}
+}
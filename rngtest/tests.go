@@ -0,0 +1,289 @@
+package rngtest
+
+import (
+	"math"
+	"sort"
+)
+
+// maxExactBins is the largest range the uniformity test bins value by value.
+const maxExactBins = 4096
+
+// sample is a run of values in [0, span); span is 2^32 for raw output.
+type sample struct {
+	name string
+	span uint64
+	vals []uint32
+}
+
+func (s *sample) u(i int) float64 {
+	return float64(s.vals[i]) / float64(s.span)
+}
+
+// Result is the outcome of one test on one sample.
+type Result struct {
+	Test      string  `json:"test"`
+	Sample    string  `json:"sample"`
+	Statistic float64 `json:"statistic"`
+	DF        int     `json:"df,omitempty"`
+	PValue    float64 `json:"pValue"`
+	Pass      bool    `json:"pass"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	Note      string  `json:"note,omitempty"`
+
+	twoTailed bool // PValue already covers both tails
+}
+
+// sidak returns the per-test level that keeps the chance of any false
+// failure among m independent tests at alpha: 1-(1-alpha)^(1/m).
+func sidak(alpha float64, m int) float64 {
+	if m <= 1 {
+		return alpha
+	}
+	return -math.Expm1(math.Log1p(-alpha) / float64(m))
+}
+
+// judgeAll judges every result at the Šidák corrected level of the suite
+// and returns that level and the overall verdict. P-values are not changed.
+func judgeAll(results []Result, alpha float64) (float64, bool) {
+	m := 0
+	for _, r := range results {
+		if !r.Skipped {
+			m++
+		}
+	}
+	level := sidak(alpha, m)
+	pass := true
+	for i := range results {
+		results[i].judge(level)
+		pass = pass && (results[i].Skipped || results[i].Pass)
+	}
+	return level, pass
+}
+
+// judge sets Pass at significance level alpha. A p-value that already
+// covers both tails fails below alpha; an upper tail p-value fails in
+// either alpha/2 tail, so a fit that is too good fails as well.
+func (r *Result) judge(alpha float64) {
+	switch {
+	case r.Skipped:
+	case r.twoTailed:
+		r.Pass = r.PValue >= alpha
+	default:
+		r.Pass = r.PValue >= alpha/2 && r.PValue <= 1-alpha/2
+	}
+}
+
+// chiSquareUniformity bins the values, exactly for small ranges and into
+// 256 bins with exact expected counts otherwise.
+func chiSquareUniformity(s *sample) Result {
+	bins := uint64(256)
+	if s.span <= maxExactBins {
+		bins = s.span
+	}
+	obs := make([]float64, bins)
+	for _, v := range s.vals {
+		obs[uint64(v)*bins/s.span]++
+	}
+	exp := make([]float64, bins)
+	n := float64(len(s.vals))
+	for b := uint64(0); b < bins; b++ {
+		// values v with floor(v*bins/span) == b
+		lo := (b*s.span + bins - 1) / bins
+		hi := ((b+1)*s.span + bins - 1) / bins
+		exp[b] = n * float64(hi-lo) / float64(s.span)
+	}
+
+	stat, df := chiSquare(obs, exp)
+	return Result{Test: "chi-square uniformity", Sample: s.name, Statistic: stat, DF: df, PValue: chiSquareP(stat, df)}
+}
+
+// serialCorrelation is Knuth's circular lag-1 serial correlation test.
+func serialCorrelation(s *sample) Result {
+	n := len(s.vals)
+	var sum, sumSq, sumLag float64
+	for i := 0; i < n; i++ {
+		u := s.u(i)
+		sum += u
+		sumSq += u * u
+		sumLag += u * s.u((i+1)%n)
+	}
+	fn := float64(n)
+	c := (fn*sumLag - sum*sum) / (fn*sumSq - sum*sum)
+	mu := -1 / (fn - 1)
+	sigma := math.Sqrt(fn*(fn-3)/(fn+1)) / (fn - 1)
+	z := (c - mu) / sigma
+	return Result{Test: "serial correlation", Sample: s.name, Statistic: c, PValue: normalP(z), twoTailed: true}
+}
+
+// runs is the Wald-Wolfowitz runs test above and below the midpoint; values
+// exactly on the midpoint are dropped.
+func runs(s *sample) Result {
+	var n1, n2, r float64
+	last := 0
+	for _, v := range s.vals {
+		twice := 2 * uint64(v)
+		cur := 0
+		switch {
+		case twice > s.span-1:
+			cur = 1
+			n1++
+		case twice < s.span-1:
+			cur = -1
+			n2++
+		default:
+			continue
+		}
+		if cur != last {
+			r++
+			last = cur
+		}
+	}
+	n := n1 + n2
+	mu := 2*n1*n2/n + 1
+	variance := 2 * n1 * n2 * (2*n1*n2 - n) / (n * n * (n - 1))
+	z := (r - mu) / math.Sqrt(variance)
+	return Result{Test: "runs", Sample: s.name, Statistic: r, PValue: normalP(z), twoTailed: true}
+}
+
+// gap is Knuth's gap test for the lower half of the range.
+func gap(s *sample) Result {
+	members := (s.span + 1) / 2
+	p := float64(members) / float64(s.span)
+
+	// longest gap category keeping the tail bin expectation reasonable
+	t := 1
+	for math.Pow(1-p, float64(t+1))*float64(len(s.vals))*p >= minExpected && t < 64 {
+		t++
+	}
+
+	obs := make([]float64, t+1)
+	gaps := 0.0
+	length := -1
+	for _, v := range s.vals {
+		if uint64(v) < members {
+			if length >= 0 {
+				if length >= t {
+					obs[t]++
+				} else {
+					obs[length]++
+				}
+				gaps++
+			}
+			length = 0
+		} else if length >= 0 {
+			length++
+		}
+	}
+
+	exp := make([]float64, t+1)
+	for k := 0; k < t; k++ {
+		exp[k] = gaps * p * math.Pow(1-p, float64(k))
+	}
+	exp[t] = gaps * math.Pow(1-p, float64(t))
+
+	stat, df := chiSquare(obs, exp)
+	return Result{Test: "gap", Sample: s.name, Statistic: stat, DF: df, PValue: chiSquareP(stat, df)}
+}
+
+// poker counts distinct digits in hands of five. Raw values give their top
+// nibble as a digit; scaled values are digits themselves when the range is
+// at most 16, larger ranges cannot be split into equiprobable digits.
+func poker(s *sample) Result {
+	d := uint64(16)
+	digit := func(v uint32) uint64 { return uint64(v) >> 28 }
+	if s.span != 1<<32 {
+		if s.span > 16 {
+			return Result{Test: "poker", Sample: s.name, Skipped: true, Pass: true, Note: "range above 16 has no equiprobable digits"}
+		}
+		d = s.span
+		digit = func(v uint32) uint64 { return uint64(v) }
+	}
+
+	// Stirling numbers of the second kind S(5, k)
+	stirling := []float64{0, 1, 15, 25, 10, 1}
+	hands := len(s.vals) / 5
+	obs := make([]float64, 6)
+	for h := 0; h < hands; h++ {
+		seen := make(map[uint64]bool, 5)
+		for i := 0; i < 5; i++ {
+			seen[digit(s.vals[h*5+i])] = true
+		}
+		obs[len(seen)]++
+	}
+
+	exp := make([]float64, 6)
+	for k := 1; k <= 5; k++ {
+		fall := 1.0
+		for j := 0; j < k; j++ {
+			fall *= float64(d) - float64(j)
+		}
+		exp[k] = float64(hands) * fall * stirling[k] / math.Pow(float64(d), 5)
+	}
+
+	stat, df := chiSquare(obs[1:], exp[1:])
+	return Result{Test: "poker", Sample: s.name, Statistic: stat, DF: df, PValue: chiSquareP(stat, df)}
+}
+
+// birthdaySpacings is Marsaglia's test. Consecutive values are combined into
+// birthdays in a year of at least 2^24 days, m birthdays are drawn so that
+// the count of duplicate spacings is Poisson with mean m^3/(4n).
+func birthdaySpacings(s *sample) Result {
+	k := 1
+	days := s.span
+	for days < 1<<24 {
+		days *= s.span
+		k++
+	}
+	m := int(math.Round(math.Cbrt(8 * float64(days))))
+	lambda := math.Pow(float64(m), 3) / (4 * float64(days))
+	reps := len(s.vals) / (m * k)
+	if reps > 1000 {
+		reps = 1000
+	}
+	if reps < 50 {
+		return Result{Test: "birthday spacings", Sample: s.name, Skipped: true, Pass: true, Note: "sample too small"}
+	}
+
+	const maxDup = 10
+	obs := make([]float64, maxDup+1)
+	bdays := make([]uint64, m)
+	spacings := make([]uint64, m)
+	pos := 0
+	for rep := 0; rep < reps; rep++ {
+		for i := 0; i < m; i++ {
+			b := uint64(0)
+			for j := 0; j < k; j++ {
+				b = b*s.span + uint64(s.vals[pos])
+				pos++
+			}
+			bdays[i] = b
+		}
+		sort.Slice(bdays, func(a, b int) bool { return bdays[a] < bdays[b] })
+		spacings[0] = bdays[0] + days - bdays[m-1]
+		for i := 1; i < m; i++ {
+			spacings[i] = bdays[i] - bdays[i-1]
+		}
+		sort.Slice(spacings, func(a, b int) bool { return spacings[a] < spacings[b] })
+		dup := 0
+		for i := 1; i < m; i++ {
+			if spacings[i] == spacings[i-1] {
+				dup++
+			}
+		}
+		if dup > maxDup {
+			dup = maxDup
+		}
+		obs[dup]++
+	}
+
+	exp := make([]float64, maxDup+1)
+	tail := 1.0
+	for j := 0; j < maxDup; j++ {
+		exp[j] = float64(reps) * poisson(lambda, j)
+		tail -= poisson(lambda, j)
+	}
+	exp[maxDup] = float64(reps) * tail
+
+	stat, df := chiSquare(obs, exp)
+	return Result{Test: "birthday spacings", Sample: s.name, Statistic: stat, DF: df, PValue: chiSquareP(stat, df)}
+}
@@ -0,0 +1,82 @@
+package rngtest
+
+import (
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+)
+
+const testValues = 200000
+
+// drbgSamples draws the raw and the range 16 sample from a fixed seed, so
+// the expected passes do not depend on chance.
+func drbgSamples(t *testing.T) []*sample {
+	t.Helper()
+	d := rng.NewDRBG([]byte("rngtest known answer"))
+	raw, err := d.GetRawNumbers(testValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := d.GetRandomNumbers(testValues, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// as pull does, the client returns the accepted raw values
+	for i := range small {
+		small[i] %= 16
+	}
+	return []*sample{
+		{name: "raw32", span: 1 << 32, vals: raw},
+		{name: "range16", span: 16, vals: small},
+	}
+}
+
+// biased returns a raw sample of n values from fn.
+func biased(fn func(i int) uint32) *sample {
+	s := &sample{name: "biased", span: 1 << 32, vals: make([]uint32, testValues)}
+	for i := range s.vals {
+		s.vals[i] = fn(i)
+	}
+	return s
+}
+
+func TestStatisticalTests(t *testing.T) {
+	good := drbgSamples(t)
+	d := rng.NewDRBG([]byte("rngtest biased"))
+	noise, _ := d.GetRawNumbers(testValues)
+
+	tests := []struct {
+		name string
+		test func(*sample) Result
+		bad  *sample
+	}{
+		// nothing in the upper half
+		{"chi-square uniformity", chiSquareUniformity, biased(func(i int) uint32 { return noise[i] >> 1 })},
+		// each value close to the previous one
+		{"serial correlation", serialCorrelation, biased(func(i int) uint32 { return uint32(i) << 14 })},
+		// strictly alternating halves
+		{"runs", runs, biased(func(i int) uint32 { return noise[i]>>1 | uint32(i%2)<<31 })},
+		// the lower half comes every other value
+		{"gap", gap, biased(func(i int) uint32 { return noise[i]>>1 | uint32(i%2)<<31 })},
+		// the top nibble only takes four values
+		{"poker", poker, biased(func(i int) uint32 { return noise[i] &^ (3 << 30) })},
+		// evenly spaced birthdays
+		{"birthday spacings", birthdaySpacings, biased(func(i int) uint32 { return uint32(i) * 2654435769 })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range good {
+				r := tt.test(s)
+				r.judge(0.01)
+				if r.Skipped || !r.Pass {
+					t.Errorf("%s on DRBG %s = %+v, want pass", tt.name, s.name, r)
+				}
+			}
+			r := tt.test(tt.bad)
+			r.judge(0.01)
+			if r.Skipped || r.Pass {
+				t.Errorf("%s on biased input = %+v, want fail", tt.name, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// RequestPlay.command 支持的指令
+const (
+	CmdSpin   = "spin"   // 用 clientParams 中的下注开局, 为空时的默认指令
+	CmdRebet  = "rebet"  // 重复玩家状态中上一局的下注
+	CmdDouble = "double" // 上一局的下注加倍后开局
+	CmdClear  = "clear"  // 清除上一局的下注, 不开局
+)
+
+// resolveBets 按指令得到本局的下注
+// rebet 和 double 忽略 clientParams 中的下注, 只使用其中的 clientSeed
+// maxStake 大于 0 时本局下注总额不能超过它; 加倍或求和溢出 int64 的下注总是拒绝
+func resolveBets(req *proto.RequestPlay, player *playerState, maxStake int64) (*proto.BetRequest, error) {
+	var breq proto.BetRequest
+	switch req.Command {
+	case "", CmdSpin:
+		if err := json.Unmarshal([]byte(req.ClientParams), &breq); err != nil {
+			return nil, fmt.Errorf("invaild bet request")
+		}
+		return &breq, checkStake(breq.Bets, maxStake)
+	case CmdRebet, CmdDouble:
+		if req.ClientParams != "" {
+			if err := json.Unmarshal([]byte(req.ClientParams), &breq); err != nil {
+				return nil, fmt.Errorf("invaild bet request")
+			}
+		}
+		if len(player.pub.LastBets) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "no previous bets to %s", req.Command)
+		}
+		breq.Bets = make([]*proto.Bet, len(player.pub.LastBets))
+		for i, bet := range player.pub.LastBets {
+			breq.Bets[i] = gproto.Clone(bet).(*proto.Bet)
+			if req.Command == CmdDouble {
+				if bet.Amount > math.MaxInt64/2 {
+					return nil, status.Errorf(codes.InvalidArgument, "doubled bet amount %d overflows", bet.Amount)
+				}
+				breq.Bets[i].Amount *= 2
+			}
+		}
+		return &breq, checkStake(breq.Bets, maxStake)
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "unknown command %q", req.Command)
+}
+
+// checkStake 检查每注金额为正, 总额不溢出且不超过 maxStake, maxStake 为 0 时不限制
+// 负数下注输了反而给玩家钱, 还能抵消总额绕过上限, 一律拒绝
+func checkStake(bets []*proto.Bet, maxStake int64) error {
+	var total int64
+	for i, bet := range bets {
+		if bet.Amount <= 0 {
+			return status.Errorf(codes.InvalidArgument, "bet %d: amount %d must be positive", i, bet.Amount)
+		}
+		if total > math.MaxInt64-bet.Amount {
+			return status.Error(codes.InvalidArgument, "total stake overflows")
+		}
+		total += bet.Amount
+	}
+	if maxStake > 0 && total > maxStake {
+		return status.Errorf(codes.InvalidArgument, "total stake %d exceeds max stake %d", total, maxStake)
+	}
+	return nil
+}
+
+// nextCommands 下一步可用的指令
+func nextCommands(player *playerState) []string {
+	if len(player.pub.LastBets) == 0 {
+		return []string{CmdSpin}
+	}
+	return []string{CmdSpin, CmdRebet, CmdDouble, CmdClear}
+}
+
+// clearBets 清除玩家状态中上一局的下注, 不开局也不消耗局号
+func (s *RouletteServer) clearBets(req *proto.RequestPlay) (*proto.ReplyPlay, error) {
+	player, err := s.claimPlayerState(req.PlayerState)
+	if err != nil {
+		return nil, err
+	}
+	// 不开局, 局号不变, 扣押的平注照旧
+	player.pub.LastBets = nil
+	ps, err := s.encodePlayerState(player)
+	if s.sessions != nil {
+		if err != nil {
+			s.sessions.abort(player)
+		} else {
+			s.sessions.commit(player)
+		}
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to marshal player state")
+		return nil, fmt.Errorf("invaild player state")
+	}
+	return &proto.ReplyPlay{
+		PlayerState:  ps,
+		Finished:     true,
+		NextCommands: nextCommands(player),
+		RoundId:      req.RoundId,
+	}, nil
+}
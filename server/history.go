@@ -0,0 +1,245 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// 历史查询的默认和最大条数
+const (
+	DefaultHistoryLimit = 100
+	MaxHistoryLimit     = 1000
+)
+
+// historyEntry 索引中的一局, 完整记录按偏移从文件读取
+type historyEntry struct {
+	table   string
+	round   uint64
+	roundID string
+	time    int64
+	players []string
+	off     int64
+	size    int
+}
+
+// History 已结算牌局的历史, 实现 RoundHistory 服务
+//
+// 每局一行 RoundInfo 的 JSON, 追加写入; 内存中只保留索引.
+type History struct {
+	proto.UnimplementedRoundHistoryServer
+
+	mu      sync.RWMutex
+	f       *os.File
+	size    int64
+	entries []historyEntry // 按写入顺序
+	byID    map[string]int // roundId 到 entries 下标
+}
+
+// OpenHistory 打开或创建历史文件并建立索引
+func OpenHistory(path string) (*History, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open round history: %v", err)
+	}
+	h := &History{f: f, byID: make(map[string]int)}
+
+	rd := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, err := rd.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(b) > 0 {
+				log.Warn().Str("path", path).Int("line", line).Msg("dropping incomplete round history record")
+			}
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if rec := bytes.TrimSpace(b); len(rec) > 0 {
+			var info proto.RoundInfo
+			if err := protojson.Unmarshal(rec, &info); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+			}
+			h.index(&info, h.size, len(rec))
+		}
+		h.size += int64(len(b))
+	}
+	// 截掉崩溃时写了一半的记录
+	if err := f.Truncate(h.size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *History) index(info *proto.RoundInfo, off int64, size int) {
+	e := historyEntry{
+		table:   info.Table,
+		round:   info.Round,
+		roundID: info.RoundId,
+		time:    info.Time,
+		off:     off,
+		size:    size,
+	}
+	for _, w := range info.Wins {
+		if p := w.GetBet().GetPlayer(); p != "" && !contains(e.players, p) {
+			e.players = append(e.players, p)
+		}
+	}
+	if e.roundID != "" {
+		h.byID[e.roundID] = len(h.entries)
+	}
+	h.entries = append(h.entries, e)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Append 记录一局, 落盘后返回
+func (h *History) Append(info *proto.RoundInfo) error {
+	b, err := protojson.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := h.f.WriteAt(append(b, '\n'), h.size); err != nil {
+		return err
+	}
+	if err := h.f.Sync(); err != nil {
+		return err
+	}
+	h.index(info, h.size, len(b))
+	h.size += int64(len(b)) + 1
+	return nil
+}
+
+// has 是否已记录桌号和局号对应的一局
+func (h *History) has(table string, round uint64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if e := &h.entries[i]; e.table == table && e.round == round {
+			return true
+		}
+	}
+	return false
+}
+
+// read 读出一条完整记录, 调用方持有读锁
+func (h *History) read(e *historyEntry) (*proto.RoundInfo, error) {
+	buf := make([]byte, e.size)
+	if _, err := h.f.ReadAt(buf, e.off); err != nil {
+		return nil, err
+	}
+	info := &proto.RoundInfo{}
+	if err := protojson.Unmarshal(buf, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetRound 按 roundId, 或按桌号和局号查询一局
+func (h *History) GetRound(ctx context.Context, req *proto.RequestGetRound) (*proto.RoundInfo, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if req.RoundId != "" {
+		if i, ok := h.byID[req.RoundId]; ok {
+			return h.read(&h.entries[i])
+		}
+		return nil, status.Errorf(codes.NotFound, "round %s not found", req.RoundId)
+	}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if e := &h.entries[i]; e.table == req.Table && e.round == req.Round {
+			return h.read(e)
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "round %s/%d not found", req.Table, req.Round)
+}
+
+// ListRounds 按桌号、玩家和时间范围查询, 新的在前
+func (h *History) ListRounds(ctx context.Context, req *proto.RequestListRounds) (*proto.ReplyListRounds, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	} else if limit > MaxHistoryLimit {
+		limit = MaxHistoryLimit
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	reply := &proto.ReplyListRounds{}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		e := &h.entries[i]
+		switch {
+		case req.Table != "" && e.table != req.Table,
+			req.Player != "" && !contains(e.players, req.Player),
+			req.From != 0 && e.time < req.From,
+			req.To != 0 && e.time >= req.To,
+			req.BeforeRound != 0 && e.round >= req.BeforeRound:
+			continue
+		}
+		if len(reply.Rounds) == limit {
+			reply.More = true
+			break
+		}
+		info, err := h.read(e)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read round history: %v", err)
+		}
+		reply.Rounds = append(reply.Rounds, info)
+	}
+	return reply, nil
+}
+
+// Close 关闭历史文件
+func (h *History) Close() error {
+	return h.f.Close()
+}
+
+// roundInfo 由结果生成一局的历史记录
+func roundInfo(table string, reply *proto.ReplyPlay) *proto.RoundInfo {
+	info := &proto.RoundInfo{
+		Table:         table,
+		Round:         reply.Round,
+		RoundId:       reply.RoundId,
+		Time:          time.Now().Unix(),
+		RandomNumbers: reply.RandomNumbers,
+	}
+	for _, r := range reply.Results {
+		var gmp proto.GameModParam
+		if r.GetClientData().GetCurGameModParam().UnmarshalTo(&gmp) != nil {
+			continue
+		}
+		info.WinningNumber = gmp.WinningNumber
+		info.Wins = append(info.Wins, gmp.Wins...)
+		info.Released = append(info.Released, gmp.Released...)
+		info.TotalWin += gmp.TotalWin
+		info.Cheated = info.Cheated || gmp.Cheated
+	}
+	return info
+}
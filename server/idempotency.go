@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// DefaultRoundRetention 已完成局的结果默认保留时间
+const DefaultRoundRetention = 10 * time.Minute
+
+// playResult 一个幂等键对应的一局
+type playResult struct {
+	reqHash [sha256.Size]byte
+	done    chan struct{} // 这一局结束后关闭
+	reply   []byte        // 序列化的 ReplyPlay
+	err     error
+	at      time.Time // 完成时间
+}
+
+// wait 等待这一局结束, 返回保存的结果
+func (r *playResult) wait(ctx context.Context) (*proto.ReplyPlay, error) {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	reply := &proto.ReplyPlay{}
+	if err := gproto.Unmarshal(r.reply, reply); err != nil {
+		return nil, status.Errorf(codes.Internal, "stored reply: %v", err)
+	}
+	return reply, nil
+}
+
+// playCache 按幂等键保存已完成局的结果, 超过保留时间后丢弃
+type playCache struct {
+	mu        sync.Mutex
+	retention time.Duration
+	m         map[string]*playResult
+	order     []string // 已完成的键, 按完成时间排列
+}
+
+func newPlayCache(retention time.Duration) *playCache {
+	if retention <= 0 {
+		retention = DefaultRoundRetention
+	}
+	return &playCache{
+		retention: retention,
+		m:         make(map[string]*playResult),
+	}
+}
+
+// hashRequest 请求内容的摘要, 同一个键只能用于同样的请求
+func hashRequest(req *proto.RequestPlay) [sha256.Size]byte {
+	b, _ := gproto.MarshalOptions{Deterministic: true}.Marshal(req)
+	return sha256.Sum256(b)
+}
+
+// begin 查找键对应的局; first 为 true 时调用方开这一局, 结束后调用 finish
+func (c *playCache) begin(req *proto.RequestPlay) (r *playResult, first bool, err error) {
+	hash := hashRequest(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	if r, ok := c.m[req.RoundId]; ok {
+		if r.reqHash != hash {
+			return nil, false, status.Errorf(codes.AlreadyExists, "round id %s was used for a different request", req.RoundId)
+		}
+		return r, false, nil
+	}
+	r = &playResult{reqHash: hash, done: make(chan struct{})}
+	c.m[req.RoundId] = r
+	return r, true, nil
+}
+
+// lookup 查找键对应的局, 不开局
+func (c *playCache) lookup(key string) (*playResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	r, ok := c.m[key]
+	return r, ok
+}
+
+// finish 保存这一局的结果; 失败的局不保存, 重试时重新开局
+func (c *playCache) finish(key string, r *playResult, reply *proto.ReplyPlay, err error) {
+	if err == nil {
+		r.reply, err = gproto.MarshalOptions{Deterministic: true}.Marshal(reply)
+	}
+	r.err = err
+
+	c.mu.Lock()
+	if err != nil {
+		delete(c.m, key)
+	} else {
+		r.at = time.Now()
+		c.order = append(c.order, key)
+	}
+	c.mu.Unlock()
+	close(r.done)
+}
+
+// expire 丢弃超过保留时间的结果
+func (c *playCache) expire(now time.Time) {
+	n := 0
+	for ; n < len(c.order); n++ {
+		r := c.m[c.order[n]]
+		if now.Sub(r.at) < c.retention {
+			break
+		}
+		delete(c.m, c.order[n])
+	}
+	c.order = c.order[n:]
+}
+
+// restore 放入重启前已完成的一局, 按完成时间依次调用
+func (c *playCache) restore(req *proto.RequestPlay, reply *proto.ReplyPlay, at time.Time) error {
+	b, err := gproto.MarshalOptions{Deterministic: true}.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	r := &playResult{reqHash: hashRequest(req), done: make(chan struct{}), reply: b, at: at}
+	close(r.done)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.m[req.RoundId]; !ok {
+		c.m[req.RoundId] = r
+		c.order = append(c.order, req.RoundId)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"sync"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/store"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// sessionLedger 跟踪持有扣押平注的会话, 防止重复使用旧的玩家状态
+//
+// 签名只能防止篡改, 不能防止重放: 客户端可以反复提交同一份持有扣押平注的旧状态,
+// 每次赢时都退回本金. 账本记录这些会话最后签发状态的局号, 只接受最新的那份,
+// 并且同一时间只允许一局使用. 不持有扣押平注的会话不跟踪, 重复使用不会多出钱;
+// 账本中没有的会话持有扣押平注时拒绝, 例如重启前或其他服务签发的旧状态.
+type sessionLedger struct {
+	mu   sync.Mutex
+	last map[string]uint64 // 会话最后签发状态的局号
+	busy map[string]bool   // 正在开局的会话
+}
+
+func newSessionLedger() *sessionLedger {
+	return &sessionLedger{
+		last: make(map[string]uint64),
+		busy: make(map[string]bool),
+	}
+}
+
+// claim 检查玩家状态是该会话最新签发的, 并占用会话到 commit 或 abort
+func (l *sessionLedger) claim(st *playerState) error {
+	id := st.priv.Session
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	last, tracked := l.last[id]
+	switch {
+	case !tracked && len(st.priv.EnPrison) > 0:
+		return status.Error(codes.FailedPrecondition, "player state holds En Prison stakes unknown to this server")
+	case !tracked:
+		return nil
+	case l.busy[id]:
+		return status.Error(codes.FailedPrecondition, "player state is in use by another round")
+	case st.priv.Round != last:
+		return status.Error(codes.FailedPrecondition, "player state has been replaced by a newer one")
+	}
+	l.busy[id] = true
+	st.claimed = id
+	return nil
+}
+
+// commit 应答发出前记录新签发的状态, 不再持有扣押平注的会话不再跟踪
+func (l *sessionLedger) commit(st *playerState) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if st.claimed != "" {
+		delete(l.busy, st.claimed)
+		st.claimed = ""
+	}
+	l.track(st.priv)
+}
+
+// abort 本局失败, 释放会话, 客户端继续使用原来的状态
+func (l *sessionLedger) abort(st *playerState) {
+	if st == nil || st.claimed == "" {
+		return
+	}
+	l.mu.Lock()
+	delete(l.busy, st.claimed)
+	l.mu.Unlock()
+	st.claimed = ""
+}
+
+func (l *sessionLedger) track(priv *proto.RoulettePrivateState) {
+	if len(priv.EnPrison) > 0 {
+		l.last[priv.Session] = priv.Round
+	} else {
+		delete(l.last, priv.Session)
+	}
+}
+
+// load 按局号顺序用已结算局应答中的玩家状态重建账本, 启动时调用
+func (l *sessionLedger) load(rounds []*store.Round) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, r := range rounds {
+		if r.State != store.StateSettled || r.Reply == nil {
+			continue
+		}
+		var reply proto.ReplyPlay
+		if err := protojson.Unmarshal(r.Reply, &reply); err != nil {
+			log.Warn().Err(err).Uint64("round", r.Round).Msg("unreadable reply in round store")
+			continue
+		}
+		if reply.PlayerState.GetPrivate() == nil {
+			continue
+		}
+		var priv proto.RoulettePrivateState
+		if err := reply.PlayerState.Private.UnmarshalTo(&priv); err != nil {
+			log.Warn().Err(err).Uint64("round", r.Round).Msg("unreadable player state in round store")
+			continue
+		}
+		l.track(&priv)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/store"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// beginRound 开奖前记录下注
+func (s *RouletteServer) beginRound(round uint64, req *proto.RequestPlay) error {
+	b, err := protojson.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return s.store.Begin(&store.Round{
+		Table:   s.table,
+		Round:   round,
+		RoundID: req.RoundId,
+		Request: b,
+	})
+}
+
+// finishRound 记录开奖结果后结算, 或者作废
+// 开奖结果中的 ReplyPlay 同时带随机数和每个下注的结果; 结算记录没有落盘时重启后用它结算
+func (s *RouletteServer) finishRound(round uint64, reply *proto.ReplyPlay, err error) error {
+	if err != nil {
+		return s.store.Void(s.table, round, err.Error())
+	}
+	b, err := protojson.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Outcome(s.table, round, b); err != nil {
+		return err
+	}
+	return s.store.Settle(s.table, round, nil)
+}
+
+// recoverRounds 处理崩溃时没有结束的局, 并恢复保留时间内按 roundId 完成的局
+//
+// 结果只在结算记录落盘后才发出, 所以没有开奖结果的局从未被玩家看到, 直接作废;
+// 已有开奖结果的局用记录的结果结算, 重试同一 roundId 时返回该结果.
+func (s *RouletteServer) recoverRounds(retention time.Duration) error {
+	if last := s.store.Last(s.table); last > s.round {
+		s.round = last
+	}
+
+	for _, r := range s.store.Unfinished(s.table) {
+		if r.State == store.StateOutcome {
+			log.Warn().Str("table", r.Table).Uint64("round", r.Round).Msg("settling unfinished round with its recorded outcome")
+			if err := s.store.Settle(r.Table, r.Round, nil); err != nil {
+				return err
+			}
+			s.recoverHistory(r)
+			continue
+		}
+		log.Warn().Str("table", r.Table).Uint64("round", r.Round).Msg("voiding unfinished round without an outcome")
+		if err := s.store.Void(r.Table, r.Round, "server restarted before the spin"); err != nil {
+			return err
+		}
+	}
+
+	if retention <= 0 {
+		retention = DefaultRoundRetention
+	}
+	all, err := s.store.Recent(s.table, time.Time{})
+	if err != nil {
+		return err
+	}
+	// 所有已结算局的玩家状态重建扣押平注的账本, 重启前签发的旧状态仍然被拒绝
+	if s.sessions != nil {
+		s.sessions.load(all)
+	}
+	since := time.Now().Add(-retention)
+	restored := 0
+	for _, r := range all {
+		if r.State != store.StateSettled || r.Ended.Before(since) {
+			continue
+		}
+		// 结算后、写入历史前崩溃的局
+		s.recoverHistory(r)
+		if r.RoundID == "" {
+			continue
+		}
+		var req proto.RequestPlay
+		var reply proto.ReplyPlay
+		if protojson.Unmarshal(r.Request, &req) != nil || protojson.Unmarshal(r.Reply, &reply) != nil {
+			log.Warn().Str("table", r.Table).Uint64("round", r.Round).Msg("cannot restore stored round")
+			continue
+		}
+		if err := s.plays.restore(&req, &reply, r.Ended); err != nil {
+			return err
+		}
+		restored++
+	}
+	log.Info().Str("table", s.table).Uint64("lastRound", s.round).Int("restored", restored).Msg("round store recovered")
+	return nil
+}
+
+// recoverHistory 把恢复时结算的局补入历史
+func (s *RouletteServer) recoverHistory(r *store.Round) {
+	if s.history == nil || s.history.has(r.Table, r.Round) {
+		return
+	}
+	var reply proto.ReplyPlay
+	if err := protojson.Unmarshal(r.Reply, &reply); err != nil {
+		log.Err(err).Str("table", r.Table).Uint64("round", r.Round).Msg("cannot add recovered round to history")
+		return
+	}
+	if err := s.history.Append(roundInfo(r.Table, &reply)); err != nil {
+		log.Err(err).Str("table", r.Table).Uint64("round", r.Round).Msg("failed to write round history")
+	}
+}
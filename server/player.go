@@ -0,0 +1,208 @@
+package server
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// 玩家公开状态保留的条数
+const (
+	MaxLastResults = 20
+	MaxFavourites  = 5
+)
+
+// StateKeySize 签名玩家私有状态的密钥长度
+const StateKeySize = 32
+
+// playerState 一个玩家会话的状态, 随 RequestPlay.playerState 往返
+// 服务端只在账本中记录持有扣押平注的会话最后签发的局号
+type playerState struct {
+	pub  *proto.RoulettePublicState
+	priv *proto.RoulettePrivateState
+
+	claimed string // 在账本中占用的会话, 见 sessionLedger
+}
+
+// decodePlayerState 解析请求中的玩家状态, 为空时是新会话, 不检查签名
+func decodePlayerState(ps *proto.PlayerState) (*playerState, error) {
+	st := &playerState{
+		pub:  &proto.RoulettePublicState{},
+		priv: &proto.RoulettePrivateState{},
+	}
+	if pub := ps.GetPublic(); pub != nil {
+		if err := pub.UnmarshalTo(st.pub); err != nil {
+			return nil, fmt.Errorf("invalid public player state: %v", err)
+		}
+	}
+	if priv := ps.GetPrivate(); priv != nil {
+		if err := priv.UnmarshalTo(st.priv); err != nil {
+			return nil, fmt.Errorf("invalid private player state: %v", err)
+		}
+	}
+	return st, nil
+}
+
+// stateMAC 私有状态的签名, 计算时不含 mac 字段
+func stateMAC(key []byte, priv *proto.RoulettePrivateState) ([]byte, error) {
+	cp := gproto.Clone(priv).(*proto.RoulettePrivateState)
+	cp.Mac = nil
+	b, err := gproto.MarshalOptions{Deterministic: true}.Marshal(cp)
+	if err != nil {
+		return nil, err
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write(b)
+	return m.Sum(nil), nil
+}
+
+// loadPlayerState 解析请求中的玩家状态并检查私有状态的签名
+// 私有状态中有扣押的本金和会话计数, 只接受本服务(或共用 -stateKey 的服务)签发的
+func (s *RouletteServer) loadPlayerState(ps *proto.PlayerState) (*playerState, error) {
+	st, err := decodePlayerState(ps)
+	if err != nil {
+		return nil, err
+	}
+	if ps.GetPrivate() != nil && s.stateKey != nil {
+		mac, err := stateMAC(s.stateKey, st.priv)
+		if err != nil {
+			return nil, err
+		}
+		if !hmac.Equal(mac, st.priv.Mac) {
+			return nil, fmt.Errorf("private player state is not signed by this server")
+		}
+	}
+	st.priv.Mac = nil
+	return st, nil
+}
+
+// claimPlayerState 解析并检查玩家状态, 持有扣押平注的会话在账本中占用到 commit 或 abort
+func (s *RouletteServer) claimPlayerState(ps *proto.PlayerState) (*playerState, error) {
+	st, err := s.loadPlayerState(ps)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.sessions != nil {
+		if err := s.sessions.claim(st); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// newSessionID 随机生成会话ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	crand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// encodePlayerState 签名私有状态, 生成应答中的玩家状态
+func (s *RouletteServer) encodePlayerState(st *playerState) (*proto.PlayerState, error) {
+	mac, err := stateMAC(s.stateKey, st.priv)
+	if err != nil {
+		return nil, err
+	}
+	st.priv.Mac = mac
+	pub, err := anypb.New(st.pub)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := anypb.New(st.priv)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PlayerState{Public: pub, Private: priv}, nil
+}
+
+// release 用本局的获胜数字决定扣押中的平注: 赢时退回本金, 否则输掉
+// 再次开 0 时同样输掉
+func (st *playerState) release(winningNumber int) []*proto.BetWin {
+	var released []*proto.BetWin
+	for _, bet := range st.priv.EnPrison {
+		numbers := make([]int, len(bet.Numbers))
+		for i, n := range bet.Numbers {
+			numbers[i] = int(n)
+		}
+		betType, err := game.DetermineBetType(numbers)
+		if err != nil {
+			continue
+		}
+		win := game.CheckWin(betType, numbers, winningNumber)
+		bw := &proto.BetWin{Bet: bet, BetType: string(betType), Win: win}
+		if win {
+			bw.WinAmount = bet.Amount
+		}
+		released = append(released, bw)
+	}
+	st.priv.EnPrison = nil
+	return released
+}
+
+// record 把本局计入会话状态
+func (st *playerState) record(bets []*proto.Bet, gmp *proto.GameModParam) {
+	st.pub.LastResults = append([]int32{gmp.WinningNumber}, st.pub.LastResults...)
+	if len(st.pub.LastResults) > MaxLastResults {
+		st.pub.LastResults = st.pub.LastResults[:MaxLastResults]
+	}
+	if len(bets) > 0 {
+		st.pub.LastBets = bets
+		st.favourite(bets)
+	}
+
+	st.priv.EnPrison = append(st.priv.EnPrison, gmp.Imprisoned...)
+	st.priv.Rounds++
+	for _, bet := range bets {
+		st.priv.TotalBet += bet.Amount
+	}
+	st.priv.TotalWin += gmp.TotalWin
+}
+
+// favourite 增加下注组合的使用次数, 只保留最常用的几个
+func (st *playerState) favourite(bets []*proto.Bet) {
+	found := false
+	for _, l := range st.pub.Favourites {
+		if sameLayout(l.Bets, bets) {
+			l.Count++
+			found = true
+			break
+		}
+	}
+	if !found {
+		st.pub.Favourites = append(st.pub.Favourites, &proto.BetLayout{Bets: bets, Count: 1})
+	}
+	// 次数相同时保持原有顺序
+	sort.SliceStable(st.pub.Favourites, func(i, j int) bool {
+		return st.pub.Favourites[i].Count > st.pub.Favourites[j].Count
+	})
+	if len(st.pub.Favourites) > MaxFavourites {
+		st.pub.Favourites = st.pub.Favourites[:MaxFavourites]
+	}
+}
+
+func sameLayout(a, b []*proto.Bet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !gproto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// evenMoney 是否为 En Prison 适用的平注
+func evenMoney(betType game.BetType) bool {
+	return betType == game.OddEven || betType == game.RedBlack || betType == game.HighLow
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/rng"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPoolReport 默认每隔多久在日志中报告一次RNG预取池指标
+const DefaultPoolReport = time.Minute
+
+// reportPool 每隔 interval 在日志中报告预取池指标, 返回停止函数
+// 本周期内有同步请求或补充失败时用 warn 级别, 说明池太小或RNG服务跟不上
+func reportPool(stats func() rng.PoolStats, table string, interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var prev rng.PoolStats
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			cur := stats()
+			hits, misses := cur.Hits-prev.Hits, cur.Misses-prev.Misses
+			refillErrors := cur.RefillErrors - prev.RefillErrors
+
+			ev := log.Info()
+			if misses > 0 || refillErrors > 0 {
+				ev = log.Warn()
+			}
+			ev.Str("table", table).
+				Int("size", cur.Size).
+				Uint64("hits", hits).
+				Uint64("misses", misses).
+				Uint64("refills", cur.Refills-prev.Refills).
+				Uint64("refillErrors", refillErrors).
+				Uint64("totalHits", cur.Hits).
+				Uint64("totalMisses", cur.Misses).
+				Dur("interval", interval).
+				Msg("RNG pool stats")
+			prev = cur
+		}
+	}()
+	return func() { close(done) }
+}
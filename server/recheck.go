@@ -0,0 +1,336 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/rng"
+	"gitee.com/heartfun/rouletteserv/store"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// recorded 重新结算需要的一局记录
+type recorded struct {
+	table    string
+	round    uint64
+	roundID  string
+	version  string              // 记录时的游戏版本, 未知时为空
+	settings *RoundSettings      // 记录时的配置, 只有每局记录中有
+	req      *proto.RequestPlay  // 下注请求, 历史文件中没有
+	gmp      *proto.GameModParam // 获胜数字、每个下注的结果和总赢分
+	rngs     []*proto.RngInfo
+}
+
+// Recheck 用当前代码重新结算记录文件中的局, 确认记录的赔付
+//
+// path 可以是每局记录 (-roundLog)、牌局记录 (-store) 或历史文件 (-history).
+// 对每个下注重新执行 DetermineBetType、CheckWin 和 CalculatePayout, 并用记录的
+// 随机数重新计算获胜数字; round 为 0 时检查 table 的所有局, table 为空时检查所有桌.
+// 每局记录中有当时的 En Prison 开关和最大下注时按它们检查, 与本次的配置无关.
+func Recheck(kind, path, table string, round uint64) error {
+	checked, mismatched := 0, 0
+	err := readRecorded(kind, path, func(r *recorded) error {
+		if (table != "" && r.table != table) || (round != 0 && r.round != round) {
+			return nil
+		}
+		checked++
+		l := log.Info()
+		problems := recheckRound(r)
+		if len(problems) > 0 {
+			mismatched++
+			l = log.Error().Strs("problems", problems)
+		}
+		if r.version != "" && r.version != game.Version {
+			l = l.Str("recordedVersion", r.version)
+		}
+		l.Str("table", r.table).
+			Uint64("round", r.round).
+			Str("roundId", r.roundID).
+			Int32("winningNumber", r.gmp.WinningNumber).
+			Int64("totalWin", r.gmp.TotalWin).
+			Bool("ok", len(problems) == 0).
+			Msg("recheck")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("version", game.Version).
+		Int("checked", checked).
+		Int("mismatched", mismatched).
+		Msg("recheck finished")
+	if checked == 0 {
+		return fmt.Errorf("no recorded round matches")
+	}
+	if mismatched > 0 {
+		return fmt.Errorf("%d of %d rounds do not settle the same", mismatched, checked)
+	}
+	return nil
+}
+
+// recheckRound 返回一局中与当前代码不一致的地方
+func recheckRound(r *recorded) []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	winning := int(r.gmp.WinningNumber)
+
+	// 记录的下注必须就是请求中的下注, rebet 和 double 为玩家状态中上一局的下注
+	var player *playerState
+	if r.req != nil {
+		var err error
+		if player, err = decodePlayerState(r.req.PlayerState); err != nil {
+			add("invalid player state: %v", err)
+		}
+	}
+	if player != nil {
+		// 没有记录配置时不限制最大下注
+		var maxStake int64
+		if r.settings != nil {
+			maxStake = r.settings.MaxStake
+		}
+		breq, err := resolveBets(r.req, player, maxStake)
+		if err != nil {
+			add("invalid bet request: %v", err)
+		} else if len(breq.Bets) != len(r.gmp.Wins) {
+			add("%d bets requested, %d settled", len(breq.Bets), len(r.gmp.Wins))
+		} else {
+			for i, bet := range breq.Bets {
+				got := r.gmp.Wins[i].GetBet()
+				if fmt.Sprint(bet.Numbers) != fmt.Sprint(got.GetNumbers()) || bet.Amount != got.GetAmount() {
+					add("bet %d: requested %v x %d, settled %v x %d", i, bet.Numbers, bet.Amount, got.GetNumbers(), got.GetAmount())
+				}
+			}
+		}
+	}
+
+	// 每个下注重新结算
+	var total int64
+	for i, w := range r.gmp.Wins {
+		numbers := make([]int, len(w.GetBet().GetNumbers()))
+		for j, n := range w.GetBet().GetNumbers() {
+			numbers[j] = int(n)
+		}
+		betType, err := game.DetermineBetType(numbers)
+		if err != nil {
+			add("bet %d %v: %v", i, numbers, err)
+			continue
+		}
+		win := game.CheckWin(betType, numbers, winning)
+		var amount int64
+		if win {
+			amount = game.CalculatePayout(betType, w.GetBet().GetAmount())
+		}
+		if string(betType) != w.BetType {
+			add("bet %d %v: type %s, recorded %s", i, numbers, betType, w.BetType)
+		}
+		if win != w.Win || amount != w.WinAmount {
+			add("bet %d %v: win %v %d, recorded %v %d", i, numbers, win, amount, w.Win, w.WinAmount)
+		}
+		if int32(game.Payouts[betType]) != w.Payout {
+			add("bet %d %v: payout %d, recorded %d", i, numbers, game.Payouts[betType], w.Payout)
+		}
+		total += amount
+	}
+	// 只有开 0 时输掉的平注才能扣押
+	for i, bet := range r.gmp.Imprisoned {
+		numbers := make([]int, len(bet.Numbers))
+		for j, n := range bet.Numbers {
+			numbers[j] = int(n)
+		}
+		if betType, _ := game.DetermineBetType(numbers); winning != 0 || !evenMoney(betType) {
+			add("imprisoned bet %d %v: not an even-money bet losing to zero", i, numbers)
+		}
+	}
+
+	// 上一局扣押的平注赢时退回本金
+	for i, w := range r.gmp.Released {
+		numbers := make([]int, len(w.GetBet().GetNumbers()))
+		for j, n := range w.GetBet().GetNumbers() {
+			numbers[j] = int(n)
+		}
+		betType, err := game.DetermineBetType(numbers)
+		if err != nil {
+			add("released bet %d %v: %v", i, numbers, err)
+			continue
+		}
+		win := game.CheckWin(betType, numbers, winning)
+		var amount int64
+		if win {
+			amount = w.GetBet().GetAmount()
+		}
+		if win != w.Win || amount != w.WinAmount {
+			add("released bet %d %v: win %v %d, recorded %v %d", i, numbers, win, amount, w.Win, w.WinAmount)
+		}
+		total += amount
+	}
+	if total != r.gmp.TotalWin {
+		add("total win %d, recorded %d", total, r.gmp.TotalWin)
+	}
+	if r.settings != nil {
+		problems = append(problems, recheckEnPrison(r, player)...)
+	}
+
+	// 第一个随机数决定获胜数字, 作弊局除外
+	if r.gmp.Cheated {
+		log.Warn().Str("table", r.table).Uint64("round", r.round).Str("cheat", r.gmp.Cheat).Msg("cheated round, winning number not rechecked")
+	} else if len(r.rngs) > 0 && r.rngs[0].Range > 0 {
+		first := r.rngs[0]
+		if v, ok := rng.ScaleUniform(first.Raw, uint32(first.Range)); !ok || int(v) != winning || first.Value != int32(v) {
+			add("random number %d in range %d does not give winning number %d", first.Raw, first.Range, winning)
+		}
+		for _, rej := range first.Rejected {
+			if _, ok := rng.ScaleUniform(rej, uint32(first.Range)); ok {
+				add("rejected random number %d is acceptable in range %d", rej, first.Range)
+			}
+		}
+	}
+	return problems
+}
+
+// recheckEnPrison 按记录的 En Prison 开关检查扣押和退回的平注
+// player 为请求中的玩家状态, 没有请求时为 nil, 只检查开关关闭时没有扣押和退回
+func recheckEnPrison(r *recorded, player *playerState) []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if !r.settings.EnPrison {
+		if n := len(r.gmp.Imprisoned) + len(r.gmp.Released); n > 0 {
+			add("%d En Prison stakes settled while En Prison was off", n)
+		}
+		return problems
+	}
+	if player == nil {
+		return problems
+	}
+
+	// 开 0 时每个输掉的平注都扣押, 只在调用方保存玩家状态时
+	want := 0
+	if r.gmp.WinningNumber == 0 && r.req.PlayerState != nil {
+		for _, w := range r.gmp.Wins {
+			if evenMoney(game.BetType(w.BetType)) {
+				want++
+			}
+		}
+	}
+	if want != len(r.gmp.Imprisoned) {
+		add("%d even-money bets lost to zero, %d imprisoned", want, len(r.gmp.Imprisoned))
+	}
+
+	// 玩家状态中扣押的平注在本局全部决定
+	held := player.priv.EnPrison
+	if len(held) != len(r.gmp.Released) {
+		add("%d stakes held in the player state, %d released", len(held), len(r.gmp.Released))
+		return problems
+	}
+	for i, bet := range held {
+		got := r.gmp.Released[i].GetBet()
+		if fmt.Sprint(bet.Numbers) != fmt.Sprint(got.GetNumbers()) || bet.Amount != got.GetAmount() {
+			add("released bet %d: held %v x %d, released %v x %d", i, bet.Numbers, bet.Amount, got.GetNumbers(), got.GetAmount())
+		}
+	}
+	return problems
+}
+
+// readRecorded 按 kind 读出记录文件中每一局结算后的结果
+// kind 为 roundLog、store 或 history
+func readRecorded(kind, path string, fn func(*recorded) error) error {
+	switch kind {
+	case "roundLog":
+		return ReadRoundLog(path, func(rec *RoundRecord) error {
+			r, err := fromReply(rec.Request, rec.Reply)
+			if err != nil {
+				return fmt.Errorf("round %s/%d: %v", rec.Table, rec.Round, err)
+			}
+			r.table, r.round, r.version, r.settings = rec.Table, rec.Round, rec.Version, rec.Settings
+			return fn(r)
+		})
+	case "store":
+		rounds, err := store.ReadRounds(path)
+		if err != nil {
+			return err
+		}
+		for _, sr := range rounds {
+			if sr.State != store.StateSettled || sr.Reply == nil {
+				continue
+			}
+			r, err := fromReply(sr.Request, sr.Reply)
+			if err != nil {
+				return fmt.Errorf("round %s/%d: %v", sr.Table, sr.Round, err)
+			}
+			r.table, r.round = sr.Table, sr.Round
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "history":
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for line := 1; sc.Scan(); line++ {
+			if len(sc.Bytes()) == 0 {
+				continue
+			}
+			var info proto.RoundInfo
+			if err := protojson.Unmarshal(sc.Bytes(), &info); err != nil {
+				return fmt.Errorf("%s:%d: %v", path, line, err)
+			}
+			err := fn(&recorded{
+				table:   info.Table,
+				round:   info.Round,
+				roundID: info.RoundId,
+				gmp: &proto.GameModParam{
+					WinningNumber: info.WinningNumber,
+					Wins:          info.Wins,
+					TotalWin:      info.TotalWin,
+					Cheated:       info.Cheated,
+					Released:      info.Released,
+				},
+				rngs: info.RandomNumbers,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return sc.Err()
+	}
+	return fmt.Errorf("unknown record kind %q", kind)
+}
+
+// fromReply 从记录的请求和结果中取出结算数据
+func fromReply(reqJSON, replyJSON []byte) (*recorded, error) {
+	r := &recorded{}
+	if len(reqJSON) > 0 {
+		r.req = &proto.RequestPlay{}
+		if err := protojson.Unmarshal(reqJSON, r.req); err != nil {
+			return nil, fmt.Errorf("invalid request: %v", err)
+		}
+	}
+	var reply proto.ReplyPlay
+	if err := protojson.Unmarshal(replyJSON, &reply); err != nil {
+		return nil, fmt.Errorf("invalid reply: %v", err)
+	}
+	if len(reply.Results) == 0 {
+		return nil, fmt.Errorf("reply has no result")
+	}
+	r.gmp = &proto.GameModParam{}
+	if err := reply.Results[0].GetClientData().GetCurGameModParam().UnmarshalTo(r.gmp); err != nil {
+		return nil, fmt.Errorf("invalid game mod param: %v", err)
+	}
+	r.roundID = reply.RoundId
+	r.rngs = reply.RandomNumbers
+	return r, nil
+}
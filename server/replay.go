@@ -0,0 +1,169 @@
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/rng"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// Replay 用记录的种子重新执行记录文件中的每一局, 确认结果完全一致
+// 没有种子的局(非确定性RNG模式)、作弊局和 provably fair 局无法重放, 会被跳过并分别计数;
+// fair 局的获胜数字由服务端种子决定, 公开种子后用 -mode verify 核对
+// stateKey 为记录时的 -stateKey, 为空时不检查请求中玩家状态的签名, 也不比较应答中的签名
+func Replay(path string, stateKey []byte) error {
+	s := NewRouletteServer(nil, nil)
+	s.stateKey = stateKey
+	// 回放按记录顺序重新执行, 不检查玩家状态是否被重复使用
+	s.sessions = nil
+	replayed, skipped, fairSkipped, mismatched := 0, 0, 0, 0
+
+	err := ReadRoundLog(path, func(rec *RoundRecord) error {
+		if rec.Seed == "" {
+			skipped++
+			return nil
+		}
+		seed, err := hex.DecodeString(rec.Seed)
+		if err != nil {
+			return fmt.Errorf("round %s/%d: invalid seed: %v", rec.Table, rec.Round, err)
+		}
+
+		var req proto.RequestPlay
+		if err := protojson.Unmarshal(rec.Request, &req); err != nil {
+			return fmt.Errorf("round %s/%d: invalid request: %v", rec.Table, rec.Round, err)
+		}
+		var want proto.ReplyPlay
+		if err := protojson.Unmarshal(rec.Reply, &want); err != nil {
+			return fmt.Errorf("round %s/%d: invalid reply: %v", rec.Table, rec.Round, err)
+		}
+
+		if cheated(&want) {
+			// 作弊局的获胜数字不是由种子决定的
+			skipped++
+			return nil
+		}
+		if fairRound(&want) {
+			// fair 局不从种子取获胜数字, 之后的随机数也不同
+			fairSkipped++
+			log.Warn().Str("table", rec.Table).Uint64("round", rec.Round).Msg("provably fair round not replayed, check it with -mode verify")
+			return nil
+		}
+
+		// 回放的局都是确定性RNG, RngInfo 的来源为 seeded
+		// 按记录的配置重放; 旧记录没有配置, 有扣押或退回的平注说明当时开启了 En Prison, 没有时开关不影响结果
+		s.seed = seed
+		s.enPrison, s.maxStake = enPrisonRound(&want), 0
+		if rec.Settings != nil {
+			s.enPrison, s.maxStake = rec.Settings.EnPrison, rec.Settings.MaxStake
+		}
+		rr := rng.NewRecorder(rng.NewDRBG(seed))
+		player, err := s.claimPlayerState(req.PlayerState)
+		if err != nil {
+			return fmt.Errorf("round %s/%d: %v", rec.Table, rec.Round, err)
+		}
+		got, err := s.play(game.NewRoulette(rr), rr, rec.Round, &req, player)
+		if err != nil {
+			return fmt.Errorf("round %s/%d: replay failed: %v", rec.Table, rec.Round, err)
+		}
+
+		replayed++
+		if stateKey == nil {
+			stripStateMAC(got)
+			stripStateMAC(&want)
+		}
+		if !sameReply(got, &want) {
+			mismatched++
+			log.Error().
+				Str("table", rec.Table).
+				Uint64("round", rec.Round).
+				Str("recorded", protojson.MarshalOptions{}.Format(&want)).
+				Str("replayed", protojson.MarshalOptions{}.Format(got)).
+				Msg("replay mismatch")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("replayed", replayed).
+		Int("skipped", skipped).
+		Int("fair", fairSkipped).
+		Int("mismatched", mismatched).
+		Msg("replay finished")
+	if mismatched > 0 {
+		return fmt.Errorf("%d of %d rounds differ", mismatched, replayed)
+	}
+	return nil
+}
+
+// cheated 结果中是否有作弊局
+func cheated(reply *proto.ReplyPlay) bool {
+	for _, r := range reply.Results {
+		var gmp proto.GameModParam
+		if r.GetClientData().GetCurGameModParam().UnmarshalTo(&gmp) == nil && gmp.Cheated {
+			return true
+		}
+	}
+	return false
+}
+
+// fairRound 是否为 provably fair 局, 第一个随机数来自 fair 种子
+func fairRound(reply *proto.ReplyPlay) bool {
+	return len(reply.RandomNumbers) > 0 && reply.RandomNumbers[0].Source == "fair"
+}
+
+// enPrisonRound 结果中是否有扣押或退回的平注
+func enPrisonRound(reply *proto.ReplyPlay) bool {
+	for _, r := range reply.Results {
+		var gmp proto.GameModParam
+		if r.GetClientData().GetCurGameModParam().UnmarshalTo(&gmp) == nil && len(gmp.Imprisoned)+len(gmp.Released) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// stripStateMAC 去掉应答中私有状态的签名, 用于不知道记录时密钥的比较
+func stripStateMAC(reply *proto.ReplyPlay) {
+	priv := reply.GetPlayerState().GetPrivate()
+	if priv == nil {
+		return
+	}
+	var st proto.RoulettePrivateState
+	if priv.UnmarshalTo(&st) == nil {
+		st.Mac = nil
+		priv.MarshalFrom(&st)
+	}
+}
+
+// sameReply 比较两个结果, Any 中的局面数据按消息内容比较
+func sameReply(a, b *proto.ReplyPlay) bool {
+	if len(a.Results) != len(b.Results) {
+		return false
+	}
+	for i := range a.Results {
+		ga, gb := &proto.GameModParam{}, &proto.GameModParam{}
+		if a.Results[i].GetClientData().GetCurGameModParam().UnmarshalTo(ga) != nil ||
+			b.Results[i].GetClientData().GetCurGameModParam().UnmarshalTo(gb) != nil {
+			return false
+		}
+		if !gproto.Equal(ga, gb) {
+			return false
+		}
+	}
+
+	ca, cb := gproto.Clone(a).(*proto.ReplyPlay), gproto.Clone(b).(*proto.ReplyPlay)
+	for _, r := range append(ca.Results, cb.Results...) {
+		if r.ClientData != nil {
+			r.ClientData.CurGameModParam = nil
+		}
+	}
+	return gproto.Equal(ca, cb)
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// RoundRecord 每局记录, 按行保存为 JSON
+type RoundRecord struct {
+	Table    string          `json:"table"`
+	Round    uint64          `json:"round"`
+	Seed     string          `json:"seed,omitempty"` // 确定性RNG模式下该局的种子, hex
+	Time     time.Time       `json:"time"`
+	Version  string          `json:"version"`
+	Settings *RoundSettings  `json:"settings,omitempty"` // 记录时影响结算的配置, 旧记录中没有
+	Request  json.RawMessage `json:"request"`            // RequestPlay, protojson
+	Reply    json.RawMessage `json:"reply"`              // ReplyPlay, protojson
+}
+
+// RoundSettings 影响结算的服务配置, 重新核对时按记录的配置检查, 而不是当前的
+type RoundSettings struct {
+	EnPrison bool  `json:"enPrison"`
+	MaxStake int64 `json:"maxStake"` // 0 为不限制
+}
+
+// roundLog 追加写入的每局记录文件
+type roundLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// openRoundLog 打开记录文件, 返回该桌最后一局的局号
+func openRoundLog(path, table string) (*roundLog, uint64, error) {
+	var last uint64
+	err := ReadRoundLog(path, func(rec *RoundRecord) error {
+		if rec.Table == table && rec.Round > last {
+			last = rec.Round
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, 0, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open round log: %v", err)
+	}
+	return &roundLog{f: f}, last, nil
+}
+
+func (l *roundLog) append(table string, round uint64, seed []byte, settings *RoundSettings, req *proto.RequestPlay, reply *proto.ReplyPlay) {
+	rec := RoundRecord{
+		Table:    table,
+		Round:    round,
+		Time:     time.Now(),
+		Version:  game.Version,
+		Settings: settings,
+	}
+	if seed != nil {
+		rec.Seed = hex.EncodeToString(seed)
+	}
+	var err error
+	if rec.Request, err = protojson.Marshal(req); err != nil {
+		log.Err(err).Msg("failed to encode round request")
+		return
+	}
+	if rec.Reply, err = protojson.Marshal(reply); err != nil {
+		log.Err(err).Msg("failed to encode round reply")
+		return
+	}
+	line, _ := json.Marshal(rec)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		log.Err(err).Uint64("round", round).Msg("failed to write round log")
+	}
+}
+
+func (l *roundLog) close() error {
+	return l.f.Close()
+}
+
+// ReadRoundLog 依次读取记录文件中的每一局
+func ReadRoundLog(path string, fn func(rec *RoundRecord) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec RoundRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return fmt.Errorf("round log line %d: %v", line, err)
+		}
+		if err := fn(&rec); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
@@ -4,17 +4,23 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	crand "crypto/rand"
 	"fmt"
 	"net"
-	"strconv"
-	"strings"
+	"os"
+	"sync/atomic"
+	"time"
 
+	"gitee.com/heartfun/rouletteserv/cheat"
+	"gitee.com/heartfun/rouletteserv/fair"
 	"gitee.com/heartfun/rouletteserv/game"
 	"gitee.com/heartfun/rouletteserv/proto"
 	"gitee.com/heartfun/rouletteserv/rng"
+	"gitee.com/heartfun/rouletteserv/store"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -28,80 +34,332 @@ func (s *TestServiceServer) TestBackend(ctx context.Context, req *proto.TestBack
     return &proto.TestBackendReply{Reply: req.Message}, nil
 }
 
+// Config 轮盘服务配置
+type Config struct {
+	Port           string
+	RngAddr        string         // RNG服务地址, 为空时使用本地随机数
+	Fair           *fair.Manager  // 非空时开启 provably fair 模式
+	Seed           []byte         // 非空时使用确定性RNG, 仅用于回放和QA
+	Table          string         // 桌号, 与局号一起派生每局的种子
+	RoundLog       string         // 每局记录追加写入的文件, 可选
+	Production     bool           // 生产环境, 拒绝确定性RNG
+	RngPool        rng.PoolConfig // RNG客户端本地预取池和调用超时
+	RngPoolReport  time.Duration  // 在日志中报告预取池指标的间隔, 0 不报告
+	RngPolicy      string         // 多个RNG服务时的策略, 见 rng.PolicyFailClosed
+	AuditLog       string         // 随机数审计日志, 可选
+	Cheats         bool           // 开启作弊指令, 仅用于开发
+	RoundRetention time.Duration  // 按 roundId 保留已完成局结果的时间, 0 为默认值
+	Store          string         // 牌局持久化文件, 可选, 启动时恢复没有结束的局
+	History        string         // 牌局历史文件, 可选
+	AdminPort      string         // 运维端口, 提供 RoundHistory 查询服务; 为空时不提供
+	EnPrison       bool           // 开启 En Prison 规则
+	MaxStake       int64          // 每局下注总额上限, 0 为不限制
+	StateKey       []byte         // 签名玩家私有状态的密钥, 多个服务共用; 为空时每次启动随机生成
+}
+
 // RouletteServer 轮盘服务
 type RouletteServer struct {
 	proto.UnimplementedGameLogicServer
-	game *game.Roulette
+	fair *fair.Manager // 非空时为 provably fair 模式
+
+	seed     []byte    // 确定性RNG的主种子
+	table    string    // 桌号
+	round    uint64    // 上一局的局号, 原子操作
+	roundLog *roundLog // 可选的每局记录
+
+	rngClient game.RNGClient // 为 nil 时使用本地 crypto/rand
+	audit     *rng.AuditLog  // 可选的随机数审计日志
+	cheats    *cheat.Queue   // 本桌的作弊队列, 为 nil 时忽略作弊指令
+	enPrison  bool           // 开 0 时平注扣押到下一局
+	maxStake  int64          // 每局下注总额上限, 0 为不限制
+	stateKey  []byte         // 签名玩家私有状态的密钥, 为 nil 时不检查签名(仅回放)
+	plays     *playCache     // 按 roundId 保存的已完成局
+	store     store.Store    // 可选的牌局持久化
+	history   *History       // 可选的牌局历史
+	sessions  *sessionLedger // 持有扣押平注的会话, 为 nil 时不检查重复使用(仅回放)
+	closers   []func()       // NewServer 打开的资源
 }
 
-// NewRouletteServer 创建新的轮盘服务
-func NewRouletteServer(rngClient game.RNGClient) *RouletteServer {
+// NewRouletteServer 创建新的轮盘服务, fairMgr 为空时使用RNG
+func NewRouletteServer(rngClient game.RNGClient, fairMgr *fair.Manager) *RouletteServer {
+	// 没有配置密钥时随机生成, 只接受本进程签发的玩家状态
+	key := make([]byte, StateKeySize)
+	crand.Read(key)
 	return &RouletteServer{
-		game: game.NewRoulette(rngClient),
+		fair:      fairMgr,
+		rngClient: rngClient,
+		plays:     newPlayCache(DefaultRoundRetention),
+		stateKey:  key,
+		sessions:  newSessionLedger(),
+	}
+}
+
+// rngSource 审计日志中的随机数来源
+func (s *RouletteServer) rngSource() string {
+	switch {
+	case s.seed != nil:
+		return "seeded"
+	case s.rngClient != nil:
+		return "rng"
+	}
+	return "local"
+}
+
+// rngInfos 本局消耗的随机数, 按消耗顺序排列, 第一个决定获胜数字
+// 每项的 raw % range 等于 value, rejected 中的值都不能无偏缩放到 range
+func (s *RouletteServer) rngInfos(fairInfo *proto.RngInfo, draws []rng.Draw) []*proto.RngInfo {
+	infos := make([]*proto.RngInfo, 0, len(draws)+1)
+	if fairInfo != nil {
+		infos = append(infos, fairInfo)
+	}
+	source := s.rngSource()
+	for _, d := range draws {
+		infos = append(infos, &proto.RngInfo{
+			Bits:     32,
+			Range:    int32(d.Range),
+			Value:    int32(d.Result),
+			Raw:      d.Raw,
+			Rejected: d.Rejected,
+			Source:   source,
+		})
 	}
+	return infos
 }
 
-// Play2 处理下注请求
+// Play2 处理下注请求, command 见 CmdSpin 等
+// 带 roundId 的请求是幂等的: 保留时间内同一 roundId 的重复请求不再开局, 返回第一次的结果
 func (s *RouletteServer) Play2(ctx context.Context, req *proto.RequestPlay) (*proto.ReplyPlay, error) {
-	// 旋转轮盘获取获胜数字
-	winningNumber, err := s.game.Spin()
+	if req.Command == CmdClear {
+		return s.clearBets(req)
+	}
+	if req.RoundId == "" {
+		return s.playRound(req)
+	}
+	pr, first, err := s.plays.begin(req)
 	if err != nil {
-		log.Err(err).Msg("failed to spin roulette")
-		return nil, fmt.Errorf("failed to spin roulette")
+		return nil, err
+	}
+	if !first {
+		log.Info().Str("roundId", req.RoundId).Msg("duplicate play, returning stored result")
+		return pr.wait(ctx)
+	}
+	reply, err := s.playRound(req)
+	s.plays.finish(req.RoundId, pr, reply, err)
+	return reply, err
+}
+
+// GetResult 返回保留时间内按 roundId 完成的局, 正在进行的局等待其结束; 不开局
+// 调用方重启后用它确认没有结束的局是否已开奖, 没有结果时返回 NOT_FOUND
+func (s *RouletteServer) GetResult(ctx context.Context, req *proto.RequestResult) (*proto.ReplyPlay, error) {
+	if req.RoundId == "" {
+		return nil, status.Error(codes.InvalidArgument, "round id is required")
+	}
+	pr, ok := s.plays.lookup(req.RoundId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no result for round id %s", req.RoundId)
+	}
+	return pr.wait(ctx)
+}
+
+// playRound 开新的一局
+func (s *RouletteServer) playRound(req *proto.RequestPlay) (*proto.ReplyPlay, error) {
+	round := atomic.AddUint64(&s.round, 1)
+	var roundSeed []byte
+	src := rng.Source(s.rngClient)
+	if s.seed != nil {
+		// 确定性RNG: 每局由主种子、桌号和局号派生独立的种子
+		roundSeed = rng.RoundSeed(s.seed, s.table, round)
+		src = rng.NewDRBG(roundSeed)
+	}
+
+	// 记录本局消耗的每个随机数, 写入应答和审计日志
+	rec := rng.NewRecorder(src)
+	g := game.NewRoulette(rec)
+
+	if s.store != nil {
+		if err := s.beginRound(round, req); err != nil {
+			log.Err(err).Uint64("round", round).Msg("failed to persist round")
+			return nil, status.Error(codes.Internal, "failed to persist round")
+		}
 	}
 
+	// 持有扣押平注的会话占用到本局落盘, 失败时释放, 客户端继续使用原来的状态
+	player, err := s.claimPlayerState(req.PlayerState)
+	var result *proto.ReplyPlay
+	if err == nil {
+		result, err = s.play(g, rec, round, req, player)
+	}
+	if s.audit != nil {
+		if aerr := s.audit.Append(rng.GameCode, s.table, round, s.rngSource(), rec.Draws()); aerr != nil {
+			// 无法追溯的结果不能发出
+			log.Err(aerr).Uint64("round", round).Msg("failed to write RNG audit log")
+			result, err = nil, status.Error(codes.Internal, "failed to write RNG audit log")
+		}
+	}
+	if s.store != nil {
+		if serr := s.finishRound(round, result, err); serr != nil {
+			// 没有落盘的结果不能发出, 重启后该局作废
+			log.Err(serr).Uint64("round", round).Msg("failed to persist round")
+			result, err = nil, status.Error(codes.Internal, "failed to persist round")
+		}
+	}
+	if err != nil {
+		if s.sessions != nil {
+			s.sessions.abort(player)
+		}
+		return nil, err
+	}
+	if s.sessions != nil {
+		s.sessions.commit(player)
+	}
+
+	if s.roundLog != nil {
+		s.roundLog.append(s.table, round, roundSeed, &RoundSettings{EnPrison: s.enPrison, MaxStake: s.maxStake}, req, result)
+	}
+	if s.history != nil {
+		if err := s.history.Append(roundInfo(s.table, result)); err != nil {
+			log.Err(err).Uint64("round", round).Msg("failed to write round history")
+		}
+	}
+	return result, nil
+}
+
+// play 用指定的游戏实例和已检查的玩家状态开第 round 局
+func (s *RouletteServer) play(g *game.Roulette, rec *rng.Recorder, round uint64, req *proto.RequestPlay, player *playerState) (*proto.ReplyPlay, error) {
+	// 按指令得到下注, rebet 和 double 使用玩家状态中上一局的下注
+	breq, err := resolveBets(req, player, s.maxStake)
+	if err != nil {
+		log.Err(err).Str("command", req.Command).Msg("failed to resolve bets")
+		return nil, err
+	}
+
+	// 判断下注类型, 无效的下注在作弊指令入队和消耗随机数之前拒绝
+	betNumbers := make([][]int, len(breq.Bets))
+	betTypes := make([]game.BetType, len(breq.Bets))
+	for i, bet := range breq.Bets {
+		// 转换下注数字
+		betNumbers[i] = make([]int, len(bet.Numbers))
+		for j, n := range bet.Numbers {
+			betNumbers[i][j] = int(n)
+		}
+		if betTypes[i], err = game.DetermineBetType(betNumbers[i]); err != nil {
+			log.Err(err).Msg("invalid bet")
+			return nil, fmt.Errorf("invalid bet")
+		}
+	}
+
+	// 作弊指令只在开发环境开启, 在消耗随机数之前解析
+	// 队列属于本桌: 所有玩家的局依次使用, seq 会决定本桌接下来几局的结果
 	if req.Cheat != "" {
-		// 解析作弊数据
-		parts := strings.Split(req.Cheat, ",")
-		num, err := strconv.Atoi(parts[0])
-		if err == nil {
-			winningNumber = num % game.NumberCount
-			log.Debug().Any("cheatnum", num).Msg(req.Command)
-		} else {
-			log.Err(err).Msg("invalid cheat data")
+		switch {
+		case s.cheats == nil:
+			log.Warn().Str("cheat", req.Cheat).Msg("cheat ignored, cheats are disabled")
+		case s.fair != nil:
+			log.Warn().Str("cheat", req.Cheat).Msg("cheat ignored in provably fair mode")
+		default:
+			c, err := cheat.Parse(req.Cheat)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid cheat: %v", err)
+			}
+			if len(c.Cards) > 0 {
+				log.Warn().Str("cheat", req.Cheat).Msg("card cheat ignored, roulette deals no cards")
+			}
+			s.cheats.Push(c)
+		}
+	}
+
+	var winningNumber int
+	var proof *proto.FairProof
+	var fairInfo *proto.RngInfo
+	if s.fair != nil {
+		// provably fair: 结果由已承诺的服务端种子、玩家种子和 nonce 决定
+		draw, err := s.fair.Draw(breq.ClientSeed, game.NumberCount)
+		if err != nil {
+			log.Err(err).Msg("failed to draw provably fair result")
+			return nil, fmt.Errorf("failed to spin roulette")
+		}
+		winningNumber = draw.Number
+		proof = &proto.FairProof{
+			ServerSeedHash: draw.Hash,
+			ClientSeed:     draw.ClientSeed,
+			Nonce:          draw.Nonce,
+		}
+		fairInfo = &proto.RngInfo{
+			Bits:     32,
+			Range:    game.NumberCount,
+			Value:    int32(draw.Number),
+			Raw:      draw.Raw,
+			Rejected: draw.Rejected,
+			Source:   "fair",
+		}
+	} else {
+		// 旋转轮盘获取获胜数字
+		winningNumber, err = g.Spin()
+		if err != nil {
+			// 没有可用的RNG服务时牌桌暂停, 调用方应保留下注稍后重试
+			log.Err(err).Msg("failed to spin roulette")
+			return nil, status.Error(codes.Unavailable, "table paused: no RNG service available")
+		}
+	}
+
+	// 随机数照常消耗, 动画和 RngInfo 与正常局一致, 只有获胜数字被替换
+	var applied string
+	if s.cheats != nil && proof == nil {
+		if pocket, left, ok := s.cheats.NextPocket(); ok {
+			applied = fmt.Sprintf("pocket:%d", pocket)
+			log.Warn().
+				Str("table", s.table).
+				Uint64("round", round).
+				Int("rolled", winningNumber).
+				Int("pocket", pocket).
+				Int("left", left).
+				Msg("CHEATED ROUND - winning number forced")
+			winningNumber = pocket
 		}
 	}
 
 	result := &proto.ReplyPlay{
-		RandomNumbers: []*proto.RngInfo{{Value: int32(winningNumber + (winningNumber+1024)*game.NumberCount), Bits: 0, Range: 0}},
-		PlayerState: &proto.PlayerState{
-			Public:  nil,
-			Private: nil,
-		},
 		Finished:          true,
 		Results:           make([]*proto.GameResult, 0, 1),
-		NextCommands:      nil,
 		NextCommandParams: nil,
+		RoundId:           req.RoundId,
+		Round:             round,
 	}
 
-	var breq proto.BetRequest
-	// 解析嵌套 JSON 数据到结构体
-	err = json.Unmarshal([]byte(req.ClientParams), &breq)
+	// 结果确定后生成动画参数, 保证所有客户端和视频一致
+	anim, err := g.Animate(winningNumber)
 	if err != nil {
-		log.Err(err).Msg("failed to unmarshal nested JSON data")
-		return nil, fmt.Errorf("invaild bet request")
+		log.Err(err).Msg("failed to animate wheel")
+		return nil, fmt.Errorf("failed to animate wheel")
 	}
+	result.RandomNumbers = s.rngInfos(fairInfo, rec.Draws())
 
 	curGameModParam := &proto.GameModParam{
 		WinningNumber: int32(winningNumber),
 		Wins:          make([]*proto.BetWin, 0, len(breq.Bets)),
 		TotalWin:      0,
+		Wheel: &proto.WheelAnimation{
+			PocketIndex:     int32(anim.PocketIndex),
+			RotorStartAngle: anim.RotorStartAngle,
+			RotorSpeed:      anim.RotorSpeed,
+			BallStartAngle:  anim.BallStartAngle,
+			BallRevolutions: int32(anim.BallRevolutions),
+			Duration:        anim.Duration,
+			PocketAngle:     anim.PocketAngle,
+			LandingAngle:    anim.LandingAngle,
+		},
+		Fair:    proof,
+		Cheated: applied != "",
+		Cheat:   applied,
+	}
+	// 扣押中的平注由本局决定; 关闭 En Prison 时留在状态中, 不退回也不输掉
+	if s.enPrison {
+		curGameModParam.Released = player.release(winningNumber)
 	}
 
 	// 处理每个下注
-	for _, bet := range breq.Bets {
-		// 转换下注数字
-		numbers := make([]int, len(bet.Numbers))
-		for i, n := range bet.Numbers {
-			numbers[i] = int(n)
-		}
-
-		// 判断下注类型
-		betType, err := game.DetermineBetType(numbers)
-		if err != nil {
-			log.Err(err).Msg("invalid bet")
-			return nil, fmt.Errorf("invalid bet")
-		}
+	for i, bet := range breq.Bets {
+		numbers, betType := betNumbers[i], betTypes[i]
 
 		// 检查是否获胜
 		win := game.CheckWin(betType, numbers, winningNumber)
@@ -110,11 +368,21 @@ func (s *RouletteServer) Play2(ctx context.Context, req *proto.RequestPlay) (*pr
 			winAmount = game.CalculatePayout(betType, bet.Amount)
 		}
 
+		// En Prison: 开 0 时平注扣押到下一局, 只在调用方保存玩家状态时生效, 否则本金无处退回
+		if s.enPrison && winningNumber == 0 && evenMoney(betType) && req.PlayerState != nil {
+			curGameModParam.Imprisoned = append(curGameModParam.Imprisoned, &proto.Bet{
+				Numbers: bet.Numbers,
+				Amount:  bet.Amount,
+				Player:  bet.Player,
+			})
+		}
+
 		// 添加结果
 		curGameModParam.Wins = append(curGameModParam.Wins, &proto.BetWin{
 			Bet: &proto.Bet{
 				Numbers: bet.Numbers,
 				Amount:  bet.Amount,
+				Player:  bet.Player,
 			},
 			BetType:   string(betType),
 			Win:       win,
@@ -124,6 +392,21 @@ func (s *RouletteServer) Play2(ctx context.Context, req *proto.RequestPlay) (*pr
 
 		curGameModParam.TotalWin += winAmount
 	}
+	for _, r := range curGameModParam.Released {
+		curGameModParam.TotalWin += r.WinAmount
+	}
+
+	player.record(breq.Bets, curGameModParam)
+	// 新会话用桌号和局号命名, 回放时得到相同的会话ID
+	if player.priv.Session == "" {
+		player.priv.Session = fmt.Sprintf("%s-%d", s.table, round)
+	}
+	player.priv.Round = round
+	result.NextCommands = nextCommands(player)
+	if result.PlayerState, err = s.encodePlayerState(player); err != nil {
+		log.Err(err).Msg("failed to marshal player state")
+		return nil, fmt.Errorf("invaild player state")
+	}
 
 	anyMsg, err := anypb.New(curGameModParam)
 	if err != nil {
@@ -155,40 +438,190 @@ func (s *RouletteServer) GetConfig(ctx context.Context, req *proto.RequestConfig
 	return result, nil
 }
 
-// Initialize 初始化
+// Initialize 初始化, 返回新会话的玩家状态
 func (s *RouletteServer) Initialize(ctx context.Context, req *proto.RequestInitialize) (*proto.PlayerState, error) {
-	result := &proto.PlayerState{
-		Public:  nil,
-		Private: nil,
+	player, _ := decodePlayerState(nil)
+	player.priv.Session = newSessionID()
+	result, err := s.encodePlayerState(player)
+	if err != nil {
+		log.Err(err).Msg("failed to marshal player state")
+		return nil, status.Error(codes.Internal, "failed to marshal player state")
 	}
 
 	return result, nil
 }
 
-// StartServer 启动GRPC服务
-func StartServer(port string, rngAddr string) error {
-	var rngClient game.RNGClient
+// NewServer 按配置创建轮盘服务, 打开配置中的文件并恢复没有结束的局
+// 用完后调用 Close
+func NewServer(cfg Config) (_ *RouletteServer, err error) {
+	srv := NewRouletteServer(nil, cfg.Fair)
+	defer func() {
+		if err != nil {
+			srv.Close()
+		}
+	}()
 
-	// 如果有RNG服务地址，则创建RNG客户端
-	if rngAddr != "" {
-		client, err := rng.NewRNGClient(rngAddr)
+	if cfg.Seed != nil {
+		if cfg.Production {
+			return nil, fmt.Errorf("seeded deterministic RNG is not allowed in production")
+		}
+		log.Warn().Str("table", cfg.Table).Msg("SEEDED DETERMINISTIC RNG - every spin is reproducible, QA use only")
+	} else if cfg.RngAddr != "" {
+		// 如果有RNG服务地址，则创建RNG客户端
+		client, err := rng.NewRNGClientWithConfig(rng.ClientConfig{
+			Addrs:  rng.SplitAddrs(cfg.RngAddr),
+			Policy: cfg.RngPolicy,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to create RNG client: %v", err)
+			return nil, fmt.Errorf("failed to create RNG client: %v", err)
+		}
+		srv.closers = append(srv.closers, func() { client.Close() })
+		// 每张桌子使用独立的随机数流, 开局时从本地池取数, 不等待网络
+		stream := client.Stream(rng.GameCode, cfg.Table).Prefetch(cfg.RngPool)
+		srv.closers = append(srv.closers, func() { stream.Close() })
+		srv.rngClient = stream
+		if cfg.RngPool.High > 0 && cfg.RngPoolReport > 0 {
+			srv.closers = append(srv.closers, reportPool(stream.PoolStats, cfg.Table, cfg.RngPoolReport))
 		}
-		defer client.Close()
-		rngClient = client
 	}
 
+	srv.seed = cfg.Seed
+	srv.table = cfg.Table
+	srv.plays = newPlayCache(cfg.RoundRetention)
+	srv.enPrison = cfg.EnPrison
+	if cfg.EnPrison && cfg.Store == "" {
+		log.Warn().Str("table", cfg.Table).Msg("En Prison without -store, held stakes are rejected after a restart")
+	}
+	if cfg.MaxStake < 0 {
+		return nil, fmt.Errorf("invalid max stake %d", cfg.MaxStake)
+	}
+	srv.maxStake = cfg.MaxStake
+	if cfg.StateKey != nil {
+		srv.stateKey = cfg.StateKey
+	} else {
+		log.Warn().Str("table", cfg.Table).Msg("no -stateKey, player state from before a restart or from other servers is rejected")
+	}
+	if cfg.Cheats {
+		if cfg.Production {
+			return nil, fmt.Errorf("cheats are not allowed in production")
+		}
+		log.Warn().Str("table", cfg.Table).Msg("CHEATS ENABLED - winning numbers can be forced, development use only")
+		srv.cheats = &cheat.Queue{}
+	}
+	if cfg.RoundLog != "" {
+		rl, last, err := openRoundLog(cfg.RoundLog, cfg.Table)
+		if err != nil {
+			return nil, err
+		}
+		srv.closers = append(srv.closers, func() { rl.close() })
+		srv.roundLog = rl
+		// 继续上次的局号, 避免重复使用同一局的种子
+		srv.round = last
+	}
+	if cfg.AuditLog != "" {
+		// 没有每局记录时从审计日志继续局号, 保证按局号查询不会混淆
+		err := rng.ReadAuditLog(cfg.AuditLog, func(rec *rng.AuditRecord) error {
+			if rec.Table == cfg.Table && rec.Round > srv.round {
+				srv.round = rec.Round
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		al, err := rng.OpenAuditLog(cfg.AuditLog)
+		if err != nil {
+			return nil, err
+		}
+		srv.closers = append(srv.closers, func() { al.Close() })
+		srv.audit = al
+	}
+	// 先打开历史, 恢复时结算的局也写入历史
+	if cfg.History != "" {
+		h, err := OpenHistory(cfg.History)
+		if err != nil {
+			return nil, err
+		}
+		srv.closers = append(srv.closers, func() { h.Close() })
+		srv.history = h
+	}
+	if cfg.Store != "" {
+		st, err := store.OpenFile(cfg.Store)
+		if err != nil {
+			return nil, err
+		}
+		srv.closers = append(srv.closers, func() { st.Close() })
+		srv.store = st
+		if err := srv.recoverRounds(cfg.RoundRetention); err != nil {
+			return nil, fmt.Errorf("failed to recover rounds: %v", err)
+		}
+	}
+	return srv, nil
+}
+
+// Close 关闭 NewServer 打开的文件和连接, 按打开的相反顺序
+func (s *RouletteServer) Close() {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	s.closers = nil
+}
+
+// StartServer 启动GRPC服务
+func StartServer(cfg Config) error {
+	srv, err := NewServer(cfg)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
 	// 创建并启动服务
-	lis, err := net.Listen("tcp", ":"+port)
+	lis, err := net.Listen("tcp", ":"+cfg.Port)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
 	grpcServer := grpc.NewServer()
-	proto.RegisterGameLogicServer(grpcServer, NewRouletteServer(rngClient))
+	proto.RegisterGameLogicServer(grpcServer, srv)
 	proto.RegisterTestServiceServer(grpcServer, &TestServiceServer{})
+	if cfg.Fair != nil {
+		proto.RegisterFairServer(grpcServer, fair.NewService(cfg.Fair))
+	}
+
+	// 运维服务不放在对外端口上
+	if cfg.AdminPort != "" {
+		adminLis, err := net.Listen("tcp", ":"+cfg.AdminPort)
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("failed to listen on admin port: %v", err)
+		}
+		adminServer := grpc.NewServer()
+		if srv.history != nil {
+			proto.RegisterRoundHistoryServer(adminServer, srv.history)
+		}
+		if cfg.Fair != nil {
+			proto.RegisterFairAdminServer(adminServer, fair.NewAdminService(cfg.Fair))
+		}
+		go func() {
+			if err := adminServer.Serve(adminLis); err != nil {
+				log.Err(err).Msg("admin server stopped")
+			}
+		}()
+		defer adminServer.Stop()
+		log.Info().Msg("Starting roulette admin server on port " + cfg.AdminPort)
+	} else {
+		if srv.history != nil {
+			log.Warn().Msg("no -adminPort, RoundHistory query service is disabled")
+		}
+		if cfg.Fair != nil {
+			log.Warn().Msg("no -adminPort, FairAdmin is disabled and server seeds only rotate with -fairRotate")
+		}
+	}
 
-	log.Info().Str("rngAddr", rngAddr).Msg("Starting roulette server on port " + port)
+	log.Info().
+		Str("rngAddr", cfg.RngAddr).
+		Bool("fair", cfg.Fair != nil).
+		Bool("seeded", cfg.Seed != nil).
+		Msg("Starting roulette server on port " + cfg.Port)
 	return grpcServer.Serve(lis)
 }
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// DefaultSessionTimeout 会话回放中每个请求的超时
+const DefaultSessionTimeout = 5 * time.Second
+
+// SessionConfig 会话回放配置
+type SessionConfig struct {
+	Addr    string        // 运行中的 GameLogic 服务地址
+	Path    string        // 记录的请求文件, 每行一个请求
+	Pin     bool          // 用作弊指令固定为记录的获胜数字, 服务端需开启 -cheats
+	Timeout time.Duration // 每个请求的超时, 0 为默认值
+}
+
+// ReplaySession 把记录的请求依次发给运行中的服务, 并与记录的结果比较
+//
+// 每行是每局记录格式的 {"request": ..., "reply": ...}, 或者单独一个 RequestPlay;
+// 没有记录结果的请求只发送不比较. 要得到相同的结果, 服务端需与记录时使用相同的
+// -seed 和 -table 并从第一局开始, 或者开启 -cheats 并使用 Pin.
+// 局号不比较; Pin 时随机数、动画和作弊标记也不比较, 只比较获胜数字和结算.
+func ReplaySession(cfg SessionConfig) error {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultSessionTimeout
+	}
+	f, err := os.Open(cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	conn, err := grpc.Dial(cfg.Addr, grpc.WithInsecure())
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", cfg.Addr, err)
+	}
+	defer conn.Close()
+	client := proto.NewGameLogicClient(conn)
+
+	// 记录中的 roundId 加上前缀, 避免与服务端保留的结果冲突
+	prefix := fmt.Sprintf("replay-%d-", time.Now().UnixNano())
+	sent, failed, compared, mismatched := 0, 0, 0, 0
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		req, want, err := parseSessionLine(b)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", cfg.Path, line, err)
+		}
+		if req.RoundId != "" {
+			req.RoundId = prefix + req.RoundId
+		}
+		if cfg.Pin && want != nil {
+			if gmp := gameModParam(want); gmp != nil {
+				req.Cheat = fmt.Sprintf("pocket:%d", gmp.WinningNumber)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+		got, err := client.Play2(ctx, req)
+		cancel()
+		sent++
+		if err != nil {
+			// 记录时成功的请求现在失败, 同样是回归
+			failed++
+			log.Err(err).Int("line", line).Str("request", protojson.MarshalOptions{}.Format(req)).Msg("session replay request failed")
+			continue
+		}
+		if want == nil {
+			continue
+		}
+
+		compared++
+		if !sameSessionReply(got, want, cfg.Pin) {
+			mismatched++
+			log.Error().
+				Int("line", line).
+				Str("recorded", protojson.MarshalOptions{}.Format(want)).
+				Str("replayed", protojson.MarshalOptions{}.Format(got)).
+				Msg("session replay mismatch")
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("addr", cfg.Addr).
+		Int("sent", sent).
+		Int("failed", failed).
+		Int("compared", compared).
+		Int("mismatched", mismatched).
+		Msg("session replay finished")
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed", failed, sent)
+	}
+	if mismatched > 0 {
+		return fmt.Errorf("%d of %d replies differ", mismatched, compared)
+	}
+	return nil
+}
+
+// stripStateRound 去掉私有状态中的会话ID和签发局号
+func stripStateRound(reply *proto.ReplyPlay) {
+	priv := reply.GetPlayerState().GetPrivate()
+	if priv == nil {
+		return
+	}
+	var st proto.RoulettePrivateState
+	if priv.UnmarshalTo(&st) == nil {
+		st.Session, st.Round = "", 0
+		priv.MarshalFrom(&st)
+	}
+}
+
+// parseSessionLine 解析一行记录, 没有记录结果时 reply 为 nil
+func parseSessionLine(b []byte) (*proto.RequestPlay, *proto.ReplyPlay, error) {
+	var rec struct {
+		Request json.RawMessage `json:"request"`
+		Reply   json.RawMessage `json:"reply"`
+	}
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, nil, err
+	}
+
+	req := &proto.RequestPlay{}
+	if rec.Request == nil {
+		// 单独的 RequestPlay
+		if err := protojson.Unmarshal(b, req); err != nil {
+			return nil, nil, fmt.Errorf("not a recorded RequestPlay: %v", err)
+		}
+		return req, nil, nil
+	}
+	if err := protojson.Unmarshal(rec.Request, req); err != nil {
+		return nil, nil, fmt.Errorf("invalid request: %v", err)
+	}
+	if rec.Reply == nil {
+		return req, nil, nil
+	}
+	reply := &proto.ReplyPlay{}
+	if err := protojson.Unmarshal(rec.Reply, reply); err != nil {
+		return nil, nil, fmt.Errorf("invalid reply: %v", err)
+	}
+	return req, reply, nil
+}
+
+// gameModParam 取出第一个结果中的局面数据
+func gameModParam(reply *proto.ReplyPlay) *proto.GameModParam {
+	if len(reply.Results) == 0 {
+		return nil
+	}
+	var gmp proto.GameModParam
+	if reply.Results[0].GetClientData().GetCurGameModParam().UnmarshalTo(&gmp) != nil {
+		return nil
+	}
+	return &gmp
+}
+
+// sameSessionReply 比较回放和记录的结果, 忽略局号、roundId 和玩家私有状态的签名
+// pinned 时还忽略随机数、动画、provably fair 证明和作弊标记
+func sameSessionReply(got, want *proto.ReplyPlay, pinned bool) bool {
+	a, b := gproto.Clone(got).(*proto.ReplyPlay), gproto.Clone(want).(*proto.ReplyPlay)
+	for _, r := range []*proto.ReplyPlay{a, b} {
+		// 私有状态的签名取决于服务端密钥, 会话ID和签发局号随局号变化, 其余内容照常比较
+		r.Round, r.RoundId = 0, ""
+		stripStateMAC(r)
+		stripStateRound(r)
+		if !pinned {
+			continue
+		}
+		r.RandomNumbers = nil
+		for _, res := range r.Results {
+			var gmp proto.GameModParam
+			if res.GetClientData().GetCurGameModParam().UnmarshalTo(&gmp) != nil {
+				continue
+			}
+			gmp.Wheel, gmp.Fair, gmp.Cheated, gmp.Cheat = nil, nil, false, ""
+			if err := res.ClientData.CurGameModParam.MarshalFrom(&gmp); err != nil {
+				return false
+			}
+		}
+	}
+	return sameReply(a, b)
+}
@@ -0,0 +1,265 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// event 日志中的一条记录, 每行一条 JSON
+type event struct {
+	Type    string          `json:"type"` // begin, outcome, settle 或 void
+	Time    time.Time       `json:"time"`
+	Table   string          `json:"table"`
+	Round   uint64          `json:"round"`
+	RoundID string          `json:"roundId,omitempty"`
+	Request json.RawMessage `json:"request,omitempty"`
+	Bets    json.RawMessage `json:"bets,omitempty"`
+	Reply   json.RawMessage `json:"reply,omitempty"`
+	Reason  string          `json:"reason,omitempty"`
+}
+
+type roundKey struct {
+	table string
+	round uint64
+}
+
+// apply 把 ev 合并到 rounds 中, 返回更新后的局
+func apply(rounds map[roundKey]*Round, ev *event) *Round {
+	key := roundKey{ev.Table, ev.Round}
+	r := rounds[key]
+	if ev.Type == "begin" || r == nil {
+		r = &Round{Table: ev.Table, Round: ev.Round, Started: ev.Time}
+		rounds[key] = r
+	}
+	switch ev.Type {
+	case "begin":
+		r.RoundID, r.Request, r.Bets, r.State = ev.RoundID, ev.Request, ev.Bets, StateBegun
+	case "outcome":
+		r.Reply, r.State = ev.Reply, StateOutcome
+	case "settle":
+		if ev.Reply != nil {
+			r.Reply = ev.Reply
+		}
+		r.State, r.Ended = StateSettled, ev.Time
+	case "void":
+		r.Reason, r.State, r.Ended = ev.Reason, StateVoid, ev.Time
+	}
+	return r
+}
+
+// FileStore 追加写入单个文件的 Store, 每次写入后落盘
+//
+// 内存中只保留没有结束的局; 崩溃时写了一半的最后一行在打开时被截掉.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	open map[roundKey]*Round // 没有结束的局
+	last map[string]uint64   // 每张桌子最后一局的局号
+}
+
+// OpenFile 打开或创建牌局记录文件, 恢复没有结束的局
+func OpenFile(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		open: make(map[roundKey]*Round),
+		last: make(map[string]uint64),
+	}
+	good, err := readEvents(path, func(ev *event) error {
+		r := apply(s.open, ev)
+		if r.Finished() {
+			delete(s.open, roundKey{r.Table, r.Round})
+		}
+		if ev.Round > s.last[ev.Table] {
+			s.last[ev.Table] = ev.Round
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open round store: %v", err)
+	}
+	// 截掉崩溃时写了一半的记录
+	if err := f.Truncate(good); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to repair round store: %v", err)
+	}
+	if _, err := f.Seek(good, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.f = f
+	return s, nil
+}
+
+// readEvents 按顺序读出每条记录, 返回最后一条完整记录之后的位置
+func readEvents(path string, fn func(*event) error) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	rd := bufio.NewReader(f)
+	var off int64
+	for line := 1; ; line++ {
+		b, err := rd.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(b) > 0 {
+				log.Warn().Str("path", path).Int("line", line).Msg("dropping incomplete round store record")
+			}
+			return off, nil
+		}
+		if err != nil {
+			return off, err
+		}
+		off += int64(len(b))
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		var ev event
+		if err := json.Unmarshal(b, &ev); err != nil {
+			return off, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if err := fn(&ev); err != nil {
+			return off, err
+		}
+	}
+}
+
+// write 追加一条记录并落盘, 然后更新内存中的状态
+func (s *FileStore) write(ev *event) error {
+	ev.Time = time.Now()
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := roundKey{ev.Table, ev.Round}
+	if _, ok := s.open[key]; !ok && ev.Type != "begin" {
+		return fmt.Errorf("round %s/%d is not in progress", ev.Table, ev.Round)
+	}
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	if r := apply(s.open, ev); r.Finished() {
+		delete(s.open, key)
+	}
+	if ev.Round > s.last[ev.Table] {
+		s.last[ev.Table] = ev.Round
+	}
+	return nil
+}
+
+func (s *FileStore) Begin(r *Round) error {
+	return s.write(&event{
+		Type:    "begin",
+		Table:   r.Table,
+		Round:   r.Round,
+		RoundID: r.RoundID,
+		Request: r.Request,
+		Bets:    r.Bets,
+	})
+}
+
+func (s *FileStore) Outcome(table string, round uint64, reply json.RawMessage) error {
+	return s.write(&event{Type: "outcome", Table: table, Round: round, Reply: reply})
+}
+
+func (s *FileStore) Settle(table string, round uint64, reply json.RawMessage) error {
+	return s.write(&event{Type: "settle", Table: table, Round: round, Reply: reply})
+}
+
+func (s *FileStore) Void(table string, round uint64, reason string) error {
+	return s.write(&event{Type: "void", Table: table, Round: round, Reason: reason})
+}
+
+func (s *FileStore) Unfinished(table string) []*Round {
+	s.mu.Lock()
+	var out []*Round
+	for key, r := range s.open {
+		if key.table == table {
+			cp := *r
+			out = append(out, &cp)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Round < out[j].Round })
+	return out
+}
+
+func (s *FileStore) Last(table string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last[table]
+}
+
+// Recent 重新读取整个文件, 只在启动时使用
+func (s *FileStore) Recent(table string, since time.Time) ([]*Round, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rounds := make(map[roundKey]*Round)
+	if _, err := readEvents(s.path, func(ev *event) error {
+		if ev.Table == table {
+			apply(rounds, ev)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var out []*Round
+	for _, r := range rounds {
+		if r.Finished() && !r.Ended.Before(since) {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Round < out[j].Round })
+	return out, nil
+}
+
+func (s *FileStore) Close() error {
+	return s.f.Close()
+}
+
+// ReadRounds 读出记录文件中的所有局, 按桌号和局号排列, 不修改文件
+func ReadRounds(path string) ([]*Round, error) {
+	rounds := make(map[roundKey]*Round)
+	if _, err := readEvents(path, func(ev *event) error {
+		apply(rounds, ev)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	out := make([]*Round, 0, len(rounds))
+	for _, r := range rounds {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Table != out[j].Table {
+			return out[i].Table < out[j].Table
+		}
+		return out[i].Round < out[j].Round
+	})
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// State 一局的状态
+type State string
+
+const (
+	StateBegun   State = "begun"   // 已记录下注, 还没有开奖结果
+	StateOutcome State = "outcome" // 已记录开奖结果, 还没有结算
+	StateSettled State = "settled" // 已结算
+	StateVoid    State = "void"    // 已作废, 下注退回
+)
+
+// Round 一局的持久化状态
+type Round struct {
+	Table   string          `json:"table"`
+	Round   uint64          `json:"round"`
+	RoundID string          `json:"roundId,omitempty"`
+	State   State           `json:"state"`
+	Started time.Time       `json:"started"`
+	Ended   time.Time       `json:"ended,omitempty"`
+	Request json.RawMessage `json:"request,omitempty"` // RequestPlay, protojson
+	Bets    json.RawMessage `json:"bets,omitempty"`    // 调用方自己的下注记录, 例如带玩家的下注
+	Reply   json.RawMessage `json:"reply,omitempty"`   // ReplyPlay, protojson, 含随机数和结算
+	Reason  string          `json:"reason,omitempty"`  // 作废原因
+}
+
+// Finished 是否已经结算或作废
+func (r *Round) Finished() bool {
+	return r.State == StateSettled || r.State == StateVoid
+}
+
+// Store 牌局持久化, 每次写入返回前都已落盘
+//
+// 一局依次经过 Begin、Outcome、Settle, 或在任意一步之后 Void.
+// 启动时 Unfinished 返回崩溃时没有结束的局, 由调用方用记录的结果结算或作废.
+type Store interface {
+	// Begin 记录开局和下注, 同一局再次调用时覆盖之前的下注
+	Begin(r *Round) error
+	// Outcome 记录开奖结果
+	Outcome(table string, round uint64, reply json.RawMessage) error
+	// Settle 记录结算, reply 为空时沿用开奖结果
+	Settle(table string, round uint64, reply json.RawMessage) error
+	// Void 作废一局
+	Void(table string, round uint64, reason string) error
+	// Unfinished 返回该桌没有结束的局, 按局号排列
+	Unfinished(table string) []*Round
+	// Last 返回该桌最后一局的局号
+	Last(table string) uint64
+	// Recent 返回该桌 since 之后结束的局
+	Recent(table string, since time.Time) ([]*Round, error)
+	Close() error
+}
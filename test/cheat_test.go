@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/cheat"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+)
+
+// TestCheatParse 检查作弊指令的语法和排队顺序
+func TestCheatParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *cheat.Cheat
+	}{
+		{"17", &cheat.Cheat{Pockets: []int{17}}},
+		{"5,anything", &cheat.Cheat{Pockets: []int{5}}},
+		{"pocket:0", &cheat.Cheat{Pockets: []int{0}}},
+		{"seq:1, 2,3", &cheat.Cheat{Pockets: []int{1, 2, 3}}},
+		{"seq:36;cards:5,12", &cheat.Cheat{Pockets: []int{36}, Cards: []uint32{5, 12}}},
+	}
+	for _, tt := range tests {
+		got, err := cheat.Parse(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "37", "pocket:1,2", "seq:-1", "cards:-3", "dice:4", "x"} {
+		if _, err := cheat.Parse(in); err == nil {
+			t.Errorf("Parse(%q) error = nil", in)
+		}
+	}
+
+	var q cheat.Queue
+	c, _ := cheat.Parse("seq:4,5;cards:9")
+	q.Push(c)
+	if p, left, ok := q.NextPocket(); !ok || p != 4 || left != 1 {
+		t.Errorf("NextPocket() = %d, %d, %v", p, left, ok)
+	}
+	c, _ = cheat.Parse("pocket:7")
+	q.Push(c)
+	if p, left, ok := q.NextPocket(); !ok || p != 7 || left != 0 {
+		t.Errorf("NextPocket() after replace = %d, %d, %v", p, left, ok)
+	}
+	if _, _, ok := q.NextPocket(); ok {
+		t.Errorf("NextPocket() on empty queue ok")
+	}
+	if card, _, ok := q.NextCard(); !ok || card != 9 {
+		t.Errorf("NextCard() = %d, %v", card, ok)
+	}
+}
+
+// TestCheatDisabled 默认不开启作弊, 指令被忽略, 结果不带作弊标记
+func TestCheatDisabled(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	forced := 0
+	for i := 0; i < 20; i++ {
+		reply, err := s.Play2(context.Background(), &proto.RequestPlay{
+			ClientParams: `{"bets":[{"numbers":[17],"amount":1}]}`,
+			Cheat:        "seq:5",
+		})
+		if err != nil {
+			t.Fatalf("Play2() error = %v", err)
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if gmp.Cheated || gmp.Cheat != "" {
+			t.Fatalf("Play2() marked cheated with cheats disabled: %v", &gmp)
+		}
+		if gmp.WinningNumber == 5 {
+			forced++
+		}
+	}
+	if forced == 20 {
+		t.Errorf("Play2() honoured the cheat with cheats disabled")
+	}
+}
+
+// TestCheatEnabled 开启作弊后按顺序强制获胜数字并标记作弊局, 无效下注不消耗指令
+func TestCheatEnabled(t *testing.T) {
+	if _, err := server.NewServer(server.Config{Cheats: true, Production: true}); err == nil {
+		t.Fatalf("NewServer() with cheats in production error = nil")
+	}
+	s, err := server.NewServer(server.Config{Table: "t1", Cheats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	params := `{"bets":[{"numbers":[17],"amount":1}]}`
+
+	// 下注无效时拒绝, 指令不入队
+	if _, err := s.Play2(ctx, &proto.RequestPlay{ClientParams: `{"bets":[{"numbers":[1,36],"amount":1}]}`, Cheat: "pocket:3"}); err == nil {
+		t.Fatalf("Play2() with an invalid bet error = nil")
+	}
+
+	want := []struct {
+		number int32
+		cheat  string
+	}{{17, "pocket:17"}, {0, "pocket:0"}, {36, "pocket:36"}}
+	for i, w := range want {
+		req := &proto.RequestPlay{ClientParams: params}
+		if i == 0 {
+			req.Cheat = "seq:17,0,36"
+		}
+		reply, err := s.Play2(ctx, req)
+		if err != nil {
+			t.Fatalf("Play2() error = %v", err)
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+			t.Fatal(err)
+		}
+		if gmp.WinningNumber != w.number || !gmp.Cheated || gmp.Cheat != w.cheat {
+			t.Errorf("round %d: winningNumber = %d, cheated = %v, cheat = %q, want %d %q", i+1, gmp.WinningNumber, gmp.Cheated, gmp.Cheat, w.number, w.cheat)
+		}
+		if i == 0 && (!gmp.Wins[0].Win || gmp.TotalWin != 36) {
+			t.Errorf("forced winning bet = %v, totalWin = %d", gmp.Wins[0], gmp.TotalWin)
+		}
+	}
+
+	// 队列用完后恢复正常
+	reply, err := s.Play2(ctx, &proto.RequestPlay{ClientParams: params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gmp proto.GameModParam
+	reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp)
+	if gmp.Cheated || gmp.Cheat != "" {
+		t.Errorf("round after the queue is empty marked cheated: %v", &gmp)
+	}
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/rng"
+)
+
+// writeManifest 把清单写入临时文件
+func writeManifest(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clips.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testClips 默认版本 cam1 覆盖全部数字, 17 有两个 take; cam2 只有 17; 空版本只有 5
+func testClips() []*game.Clip {
+	var clips []*game.Clip
+	for n := 0; n < game.NumberCount; n++ {
+		clips = append(clips, &game.Clip{ID: fmt.Sprintf("cam1_%d", n), Pocket: n, Variant: "cam1", Duration: 14, ResultAt: 10})
+	}
+	return append(clips,
+		&game.Clip{ID: "cam1_17b", Pocket: 17, Variant: "cam1", Duration: 14, ResultAt: 10},
+		&game.Clip{ID: "cam2_17", Pocket: 17, Variant: "cam2", Duration: 12, ResultAt: 9},
+		&game.Clip{ID: "plain_5", Pocket: 5, Duration: 12, ResultAt: 9},
+	)
+}
+
+// TestLoadClipLibrary 检查清单加载和校验
+func TestLoadClipLibrary(t *testing.T) {
+	data, err := json.Marshal(map[string]any{"variant": "cam1", "clips": testClips()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := game.LoadClipLibrary(writeManifest(t, string(data)))
+	if err != nil {
+		t.Fatalf("LoadClipLibrary() error = %v", err)
+	}
+	if lib.Variant != "cam1" || !lib.HasVariant("cam2") || !lib.HasVariant("") || lib.HasVariant("cam3") {
+		t.Errorf("variants: default %q, cam2 %v, empty %v, cam3 %v",
+			lib.Variant, lib.HasVariant("cam2"), lib.HasVariant(""), lib.HasVariant("cam3"))
+	}
+
+	if _, err := game.LoadClipLibrary(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing manifest should fail")
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"bad json", `{"clips": [`, "invalid clip manifest"},
+		{"missing id", `{"clips": [{"pocket": 1}]}`, "without id"},
+		{"duplicate id", `{"clips": [{"id": "a", "pocket": 1}, {"id": "a", "pocket": 2}]}`, "duplicate clip id a"},
+		{"invalid pocket", `{"clips": [{"id": "a", "pocket": 37}]}`, "invalid pocket 37"},
+		{"resultAt outside", `{"clips": [{"id": "a", "pocket": 1, "duration": 10, "resultAt": 11}]}`, "outside clip"},
+		{"negative resultAt", `{"clips": [{"id": "a", "pocket": 1, "resultAt": -1}]}`, "outside clip"},
+		{"no default variant", `{"variant": "cam1", "clips": [{"id": "a", "pocket": 1, "variant": "cam2"}]}`, `default variant "cam1"`},
+	}
+	for _, tt := range tests {
+		_, err := game.LoadClipLibrary(writeManifest(t, tt.manifest))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestClipPick 检查版本选择、回退到默认版本和 take 的随机选择
+func TestClipPick(t *testing.T) {
+	lib, err := game.NewClipLibrary("cam1", testClips())
+	if err != nil {
+		t.Fatalf("NewClipLibrary() error = %v", err)
+	}
+
+	tests := []struct {
+		pocket  int
+		variant string
+		raw     uint32
+		want    string
+	}{
+		{17, "cam2", 0, "cam2_17"},   // 本版本唯一的 take, 不消耗随机数
+		{3, "cam2", 0, "cam1_3"},     // 本版本没有该数字, 回退默认版本
+		{3, "cam3", 0, "cam1_3"},     // 没有该版本
+		{5, "", 0, "cam1_5"},         // 空版本使用默认版本, 而不是清单中版本为空的视频
+		{17, "cam1", 0, "cam1_17"},   // take 按 ID 排序, 随机数选择
+		{17, "cam1", 41, "cam1_17b"}, // 41 % 2
+	}
+	for _, tt := range tests {
+		rec := rng.NewRecorder(&rawSeq{vals: []uint32{tt.raw}})
+		clip, err := lib.Pick(tt.pocket, tt.variant, rec)
+		if err != nil {
+			t.Fatalf("Pick(%d, %q) error = %v", tt.pocket, tt.variant, err)
+		}
+		if clip.ID != tt.want {
+			t.Errorf("Pick(%d, %q) = %s, want %s", tt.pocket, tt.variant, clip.ID, tt.want)
+		}
+		draws := rec.Draws()
+		if tt.want == "cam1_17" || tt.want == "cam1_17b" {
+			if len(draws) != 1 || draws[0].Range != 2 {
+				t.Errorf("Pick(%d, %q) draws = %+v", tt.pocket, tt.variant, draws)
+			}
+		} else if len(draws) != 0 {
+			t.Errorf("Pick(%d, %q) should not draw, got %+v", tt.pocket, tt.variant, draws)
+		}
+	}
+
+	// 没有 RNG 时使用 crypto/rand
+	for i := 0; i < 20; i++ {
+		clip, err := lib.Pick(17, "cam1", nil)
+		if err != nil || (clip.ID != "cam1_17" && clip.ID != "cam1_17b") {
+			t.Fatalf("Pick(17, cam1, nil) = %v, %v", clip, err)
+		}
+	}
+
+	sparse, err := game.NewClipLibrary("", []*game.Clip{{ID: "a", Pocket: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sparse.Pick(2, "", nil); err == nil {
+		t.Error("Pick() for a pocket without clips should fail")
+	}
+}
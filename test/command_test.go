@@ -0,0 +1,142 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCommands 检查 rebet、double、clear 指令和应答中的 nextCommands
+func TestCommands(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	ctx := context.Background()
+	all := []string{server.CmdSpin, server.CmdRebet, server.CmdDouble, server.CmdClear}
+	amounts := func(reply *proto.ReplyPlay) []int64 {
+		var out []int64
+		for _, w := range gameModParam(t, reply).Wins {
+			out = append(out, w.Bet.Amount)
+		}
+		return out
+	}
+
+	reply, err := s.Play2(ctx, &proto.RequestPlay{ClientParams: `{"bets":[{"numbers":[17],"amount":10},{"numbers":[1,2],"amount":3}]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reply.NextCommands, all) {
+		t.Errorf("nextCommands after spin = %v, want %v", reply.NextCommands, all)
+	}
+
+	reply, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdRebet})
+	if err != nil {
+		t.Fatalf("rebet error = %v", err)
+	}
+	if got := amounts(reply); !reflect.DeepEqual(got, []int64{10, 3}) {
+		t.Errorf("rebet amounts = %v", got)
+	}
+
+	reply, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdDouble})
+	if err != nil {
+		t.Fatalf("double error = %v", err)
+	}
+	if got := amounts(reply); !reflect.DeepEqual(got, []int64{20, 6}) || gameModParam(t, reply).Wins[0].Bet.Numbers[0] != 17 {
+		t.Errorf("double amounts = %v", got)
+	}
+	round := reply.Round
+
+	cleared, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdClear})
+	if err != nil {
+		t.Fatalf("clear error = %v", err)
+	}
+	if len(cleared.Results) != 0 || !reflect.DeepEqual(cleared.NextCommands, []string{server.CmdSpin}) {
+		t.Errorf("clear = %v", cleared)
+	}
+	if pub, _ := playerStates(t, cleared.PlayerState); len(pub.LastBets) != 0 || len(pub.LastResults) != 3 {
+		t.Errorf("public state after clear = %v", pub)
+	}
+
+	// clear 不开局, 不消耗局号
+	reply, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: cleared.PlayerState, Command: server.CmdSpin, ClientParams: `{"bets":[]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Round != round+1 || !reflect.DeepEqual(reply.NextCommands, []string{server.CmdSpin}) {
+		t.Errorf("spin after clear round = %d, nextCommands = %v, want round %d", reply.Round, reply.NextCommands, round+1)
+	}
+
+	_, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: cleared.PlayerState, Command: server.CmdRebet})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("rebet after clear error = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Play2(ctx, &proto.RequestPlay{Command: "split"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown command error = %v, want InvalidArgument", err)
+	}
+}
+
+// TestStakeLimits 检查下注总额上限和加倍溢出
+func TestStakeLimits(t *testing.T) {
+	s, err := server.NewServer(server.Config{Table: "t1", MaxStake: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	spin := func(ps *proto.PlayerState, amounts ...int64) (*proto.ReplyPlay, error) {
+		params := `{"bets":[`
+		for i, a := range amounts {
+			if i > 0 {
+				params += ","
+			}
+			params += fmt.Sprintf(`{"numbers":[%d],"amount":%d}`, i, a)
+		}
+		return s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: params + "]}"})
+	}
+
+	reply, err := spin(nil, 40, 20)
+	if err != nil {
+		t.Fatalf("spin 60 error = %v", err)
+	}
+	if _, err := spin(nil, 60, 41); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("spin 101 error = %v, want InvalidArgument", err)
+	}
+	// 负数下注会把 150 的总额抵消到上限以下, 零下注没有意义, 都拒绝
+	for _, amounts := range [][]int64{{150, -60}, {-10}, {50, 0}} {
+		if _, err := spin(nil, amounts...); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("spin %v error = %v, want InvalidArgument", amounts, err)
+		}
+	}
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdRebet}); err != nil {
+		t.Errorf("rebet 60 error = %v", err)
+	}
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdDouble}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("double to 120 error = %v, want InvalidArgument", err)
+	}
+
+	if _, err := server.NewServer(server.Config{Table: "t1", MaxStake: -1}); err == nil {
+		t.Error("NewServer() with a negative max stake should fail")
+	}
+
+	// 没有上限时也拒绝溢出 int64 的下注
+	open := server.NewRouletteServer(nil, nil)
+	if _, err := open.Play2(ctx, &proto.RequestPlay{ClientParams: fmt.Sprintf(`{"bets":[{"numbers":[1],"amount":%d},{"numbers":[2],"amount":1}]}`, int64(math.MaxInt64))}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("overflowing total error = %v, want InvalidArgument", err)
+	}
+	half := int64(math.MaxInt64/2 + 1)
+	reply, err = open.Play2(ctx, &proto.RequestPlay{ClientParams: fmt.Sprintf(`{"bets":[{"numbers":[1],"amount":%d}]}`, half)})
+	if err != nil {
+		t.Fatalf("spin %d error = %v", half, err)
+	}
+	if _, err := open.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdDouble}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("double %d error = %v, want InvalidArgument", half, err)
+	}
+	if _, err := open.Play2(ctx, &proto.RequestPlay{PlayerState: reply.PlayerState, Command: server.CmdRebet}); err != nil {
+		t.Errorf("rebet %d error = %v", half, err)
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/fair"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+)
+
+// TestProvablyFair 开局、换种子后用公开的种子重新计算 Play2 的结果
+func TestProvablyFair(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seeds.json")
+	m, err := fair.NewManager(path, 0)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	hash, nonce := m.Commitment()
+	if nonce != 0 {
+		t.Fatalf("Commitment() nonce = %d, want 0", nonce)
+	}
+
+	s := server.NewRouletteServer(nil, m)
+	var played []*proto.GameModParam
+	for i := 0; i < 3; i++ {
+		reply, err := s.Play2(context.Background(), &proto.RequestPlay{
+			ClientParams: `{"bets":[{"numbers":[17],"amount":1}],"clientSeed":"lucky"}`,
+			Cheat:        "5",
+		})
+		if err != nil {
+			t.Fatalf("Play2() error = %v", err)
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if gmp.Fair == nil || gmp.Fair.ServerSeedHash != hash || gmp.Fair.Nonce != uint64(i) {
+			t.Fatalf("Play2() proof = %v", gmp.Fair)
+		}
+		played = append(played, &gmp)
+	}
+
+	if _, err := m.Reveal(hash); err == nil {
+		t.Fatalf("Reveal() should refuse the seed in use")
+	}
+
+	svc := fair.NewService(m)
+	rot, err := fair.NewAdminService(m).RotateSeed(context.Background(), &proto.RequestRotateSeed{})
+	if err != nil {
+		t.Fatalf("RotateSeed() error = %v", err)
+	}
+	if rot.ServerSeedHash != hash || rot.Rounds != 3 || rot.NextServerSeedHash == hash {
+		t.Fatalf("RotateSeed() = %v", rot)
+	}
+
+	for _, gmp := range played {
+		v, err := svc.Verify(context.Background(), &proto.RequestVerify{
+			ServerSeedHash: gmp.Fair.ServerSeedHash,
+			ClientSeed:     gmp.Fair.ClientSeed,
+			Nonce:          gmp.Fair.Nonce,
+		})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if !v.HashMatches || v.WinningNumber != gmp.WinningNumber {
+			t.Errorf("Verify() = %v, recorded %d", v, gmp.WinningNumber)
+		}
+	}
+
+	// 种子文件恢复后可以继续公开旧种子
+	m2, err := fair.NewManager(path, 0)
+	if err != nil {
+		t.Fatalf("NewManager() reload error = %v", err)
+	}
+	if seed, err := m2.Reveal(hash); err != nil || fair.HashSeed(seed.Seed) != hash {
+		t.Errorf("Reveal() after reload = %v, %v", seed, err)
+	}
+}
+
+// TestOutcomeDeterministic 相同输入总是得到相同结果
+// TestRotateSaveFailure 种子文件写入失败时不换种子, 当前种子也不公开
+func TestRotateSaveFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "seeds")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "seeds.json")
+	m, err := fair.NewManager(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := m.Commitment()
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Rotate(); err == nil {
+		t.Fatal("Rotate() should fail when the seed file cannot be written")
+	}
+	if cur, _ := m.Commitment(); cur != hash {
+		t.Errorf("Commitment() after failed rotation = %s, want %s", cur, hash)
+	}
+	if _, err := m.Reveal(hash); err == nil {
+		t.Error("Reveal() should refuse the seed still in use")
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old, next, err := m.Rotate()
+	if err != nil || old.Hash != hash || next == hash {
+		t.Fatalf("Rotate() = %v, %s, %v", old, next, err)
+	}
+	reloaded, err := fair.NewManager(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := reloaded.Commitment(); cur != next {
+		t.Errorf("reloaded commitment = %s, want %s", cur, next)
+	}
+	if _, err := reloaded.Reveal(hash); err != nil {
+		t.Errorf("reloaded Reveal() error = %v", err)
+	}
+}
+
+func TestOutcomeDeterministic(t *testing.T) {
+	a := fair.Outcome("seed", "client", 42, 37)
+	b := fair.Outcome("seed", "client", 42, 37)
+	if a != b || a < 0 || a >= 37 {
+		t.Errorf("Outcome() = %d, %d", a, b)
+	}
+}
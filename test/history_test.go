@@ -0,0 +1,134 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRoundHistory 检查按 roundId、玩家、桌号查询历史, 重新打开后索引不变
+func TestRoundHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h, err := server.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for round := uint64(1); round <= 5; round++ {
+		player := "alice"
+		if round%2 == 0 {
+			player = "bob"
+		}
+		err := h.Append(&proto.RoundInfo{
+			Table:         "t1",
+			Round:         round,
+			RoundId:       "r" + string(rune('0'+round)),
+			Time:          int64(1000 + round),
+			WinningNumber: int32(round),
+			Wins:          []*proto.BetWin{{Bet: &proto.Bet{Numbers: []int32{17}, Amount: 1, Player: player}}},
+			RandomNumbers: []*proto.RngInfo{{Bits: 32, Range: 37, Value: int32(round), Raw: uint32(round)}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Close()
+
+	h, err = server.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	ctx := context.Background()
+
+	r, err := h.GetRound(ctx, &proto.RequestGetRound{RoundId: "r3"})
+	if err != nil || r.Round != 3 || r.WinningNumber != 3 || r.RandomNumbers[0].Raw != 3 || r.Wins[0].Bet.Player != "alice" {
+		t.Errorf("GetRound(r3) = %v, %v", r, err)
+	}
+	if r, err := h.GetRound(ctx, &proto.RequestGetRound{Table: "t1", Round: 4}); err != nil || r.RoundId != "r4" {
+		t.Errorf("GetRound(t1/4) = %v, %v", r, err)
+	}
+	if _, err := h.GetRound(ctx, &proto.RequestGetRound{RoundId: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetRound(nope) error = %v, want NotFound", err)
+	}
+
+	list, err := h.ListRounds(ctx, &proto.RequestListRounds{Player: "alice", Limit: 2})
+	if err != nil || len(list.Rounds) != 2 || list.Rounds[0].Round != 5 || list.Rounds[1].Round != 3 || !list.More {
+		t.Errorf("ListRounds(alice) = %v, %v", list, err)
+	}
+	list, err = h.ListRounds(ctx, &proto.RequestListRounds{Table: "t1", From: 1002, To: 1004})
+	if err != nil || len(list.Rounds) != 2 || list.Rounds[0].Round != 3 || list.More {
+		t.Errorf("ListRounds(time range) = %v, %v", list, err)
+	}
+	if list, _ := h.ListRounds(ctx, &proto.RequestListRounds{Table: "t2"}); len(list.Rounds) != 0 {
+		t.Errorf("ListRounds(t2) = %v", list)
+	}
+}
+
+// TestRecoveredHistory 重启时用记录的开奖结果结算的局补入历史
+func TestRecoveredHistory(t *testing.T) {
+	dir := t.TempDir()
+	cfg := server.Config{Table: "t1", Store: filepath.Join(dir, "rounds.jsonl"), History: filepath.Join(dir, "history.jsonl")}
+	s, err := server.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := s.Play2(context.Background(), &proto.RequestPlay{ClientParams: `{"bets":[{"numbers":[17],"amount":1}]}`, RoundId: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// 模拟写入开奖结果后崩溃: 去掉结算记录和历史
+	b, err := os.ReadFile(cfg.Store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(b, []byte("\n"))
+	if err := os.WriteFile(cfg.Store, bytes.Join(lines[:2], nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.History, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = server.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	h, err := server.OpenHistory(cfg.History)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	r, err := h.GetRound(context.Background(), &proto.RequestGetRound{RoundId: "r1"})
+	if err != nil || r.Round != reply.Round || r.RandomNumbers[0].Raw != reply.RandomNumbers[0].Raw {
+		t.Fatalf("GetRound(r1) after recovery = %v, %v", r, err)
+	}
+	list, _ := h.ListRounds(context.Background(), &proto.RequestListRounds{})
+	if len(list.Rounds) != 1 {
+		t.Errorf("history has %d rounds after recovery, want 1", len(list.Rounds))
+	}
+
+	// 再次重启不重复写入
+	s, err = server.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	h2, err := server.OpenHistory(cfg.History)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h2.Close()
+	if list, _ := h2.ListRounds(context.Background(), &proto.RequestListRounds{}); len(list.Rounds) != 1 {
+		t.Errorf("history has %d rounds after second restart, want 1", len(list.Rounds))
+	}
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// TestIdempotentPlay2 同一 roundId 的重复请求返回第一次的结果, 不再开局
+func TestIdempotentPlay2(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	req := &proto.RequestPlay{
+		ClientParams: `{"bets":[{"numbers":[17],"amount":1}]}`,
+		RoundId:      "r-1",
+	}
+
+	first, err := s.Play2(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Play2() error = %v", err)
+	}
+	if first.RoundId != "r-1" || first.Round != 1 {
+		t.Fatalf("Play2() roundId = %q, round = %d", first.RoundId, first.Round)
+	}
+	want, _ := gproto.Marshal(first)
+
+	var wg sync.WaitGroup
+	replies := make([][]byte, 8)
+	for i := range replies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := s.Play2(context.Background(), gproto.Clone(req).(*proto.RequestPlay))
+			if err != nil {
+				t.Errorf("duplicate Play2() error = %v", err)
+				return
+			}
+			replies[i], _ = gproto.Marshal(reply)
+		}(i)
+	}
+	wg.Wait()
+	for i, got := range replies {
+		if !bytes.Equal(got, want) {
+			t.Errorf("duplicate %d differs from the first reply", i)
+		}
+	}
+
+	other := gproto.Clone(req).(*proto.RequestPlay)
+	other.ClientParams = `{"bets":[{"numbers":[18],"amount":1}]}`
+	if _, err := s.Play2(context.Background(), other); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Play2() with reused round id error = %v, want AlreadyExists", err)
+	}
+
+	next, err := s.Play2(context.Background(), &proto.RequestPlay{ClientParams: req.ClientParams})
+	if err != nil || next.Round != 2 {
+		t.Errorf("Play2() without round id = round %d, %v, want a new round 2", next.GetRound(), err)
+	}
+}
+
+// TestGetResult 按 roundId 查询完成的局, 不开局; 牌局记录先写开奖结果再结算
+func TestGetResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rounds.jsonl")
+	s, err := server.NewServer(server.Config{Table: "t1", Store: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := s.GetResult(ctx, &proto.RequestResult{RoundId: "r-1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetResult() before the round error = %v, want NotFound", err)
+	}
+	if _, err := s.GetResult(ctx, &proto.RequestResult{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetResult() without round id error = %v, want InvalidArgument", err)
+	}
+
+	reply, err := s.Play2(ctx, &proto.RequestPlay{ClientParams: `{"bets":[{"numbers":[17],"amount":1}]}`, RoundId: "r-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetResult(ctx, &proto.RequestResult{RoundId: "r-1"})
+	if err != nil || !gproto.Equal(got, reply) {
+		t.Errorf("GetResult() = %v, %v, want the Play2 reply", got, err)
+	}
+	s.Close()
+
+	// 结算记录丢失时, 重启后用记录的开奖结果结算并恢复按 roundId 的结果
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(bytes.TrimSpace(b), []byte("\n"))
+	if len(lines) != 3 || !bytes.Contains(lines[1], []byte(`"outcome"`)) || !bytes.Contains(lines[2], []byte(`"settle"`)) {
+		t.Fatalf("store = %s, want begin, outcome and settle", b)
+	}
+	if err := os.WriteFile(path, bytes.Join(lines[:2], nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err = server.NewServer(server.Config{Table: "t1", Store: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err = s.GetResult(ctx, &proto.RequestResult{RoundId: "r-1"})
+	if err != nil || got.Round != 1 || !gproto.Equal(got.RandomNumbers[0], reply.RandomNumbers[0]) {
+		t.Errorf("GetResult() after restart = %v, %v", got, err)
+	}
+}
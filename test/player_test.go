@@ -0,0 +1,206 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func playerStates(t *testing.T, ps *proto.PlayerState) (*proto.RoulettePublicState, *proto.RoulettePrivateState) {
+	t.Helper()
+	pub, priv := &proto.RoulettePublicState{}, &proto.RoulettePrivateState{}
+	if err := ps.GetPublic().UnmarshalTo(pub); err != nil {
+		t.Fatalf("public player state: %v", err)
+	}
+	if err := ps.GetPrivate().UnmarshalTo(priv); err != nil {
+		t.Fatalf("private player state: %v", err)
+	}
+	return pub, priv
+}
+
+func gameModParam(t *testing.T, reply *proto.ReplyPlay) *proto.GameModParam {
+	t.Helper()
+	var gmp proto.GameModParam
+	if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	return &gmp
+}
+
+// TestPlayerState 检查玩家状态随请求往返, 扣押的平注由下一局决定
+func TestPlayerState(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	ctx := context.Background()
+
+	ps, err := s.Initialize(ctx, &proto.RequestInitialize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub, priv := playerStates(t, ps); len(pub.LastResults) != 0 || priv.Rounds != 0 {
+		t.Errorf("Initialize() = %v, %v", pub, priv)
+	}
+
+	params := `{"bets":[{"numbers":[17],"amount":10},{"numbers":[0],"amount":5}]}`
+	var results []int32
+	for i := 0; i < 3; i++ {
+		reply, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: params})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ps = reply.PlayerState
+		results = append([]int32{gameModParam(t, reply).WinningNumber}, results...)
+	}
+	pub, priv := playerStates(t, ps)
+	if len(pub.LastResults) != 3 || pub.LastResults[0] != results[0] || pub.LastResults[2] != results[2] {
+		t.Errorf("lastResults = %v, want %v", pub.LastResults, results)
+	}
+	if len(pub.LastBets) != 2 || pub.LastBets[0].Numbers[0] != 17 || len(pub.Favourites) != 1 || pub.Favourites[0].Count != 3 {
+		t.Errorf("lastBets = %v, favourites = %v", pub.LastBets, pub.Favourites)
+	}
+	if priv.Rounds != 3 || priv.TotalBet != 45 {
+		t.Errorf("rounds = %d, totalBet = %d", priv.Rounds, priv.TotalBet)
+	}
+
+	// 篡改的私有状态
+	priv.TotalBet = 0
+	ps.Private, _ = anypb.New(priv)
+	_, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: params})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Play2() with forged private state error = %v, want InvalidArgument", err)
+	}
+
+	// 类型不对的状态
+	bad, _ := anypb.New(&proto.Bet{})
+	_, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: &proto.PlayerState{Public: bad}, ClientParams: params})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Play2() with invalid player state error = %v, want InvalidArgument", err)
+	}
+}
+
+// TestEnPrison 开 0 时扣押输掉的平注, 下一局赢时退回本金; 关闭 En Prison 的服务不退回
+func TestEnPrison(t *testing.T) {
+	key := make([]byte, server.StateKeySize)
+	s, err := server.NewServer(server.Config{Table: "t1", Cheats: true, EnPrison: true, StateKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	odd := `{"bets":[{"numbers":[1,3,5,7,9,11,13,15,17,19,21,23,25,27,29,31,33,35],"amount":8}]}`
+
+	ps, err := s.Initialize(ctx, &proto.RequestInitialize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: odd, Cheat: "pocket:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gmp := gameModParam(t, reply); len(gmp.Imprisoned) != 1 || gmp.TotalWin != 0 {
+		t.Fatalf("imprisoned = %v, totalWin = %d", gmp.Imprisoned, gmp.TotalWin)
+	}
+	held := reply.PlayerState
+	if _, priv := playerStates(t, held); len(priv.EnPrison) != 1 || priv.EnPrison[0].Amount != 8 {
+		t.Fatalf("private state after zero = %v", priv)
+	}
+
+	// 伪造扣押的本金
+	_, priv := playerStates(t, held)
+	priv.EnPrison[0].Amount = 800
+	forged := &proto.PlayerState{Public: held.Public}
+	forged.Private, _ = anypb.New(priv)
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: forged, ClientParams: `{"bets":[]}`}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Play2() with forged En Prison error = %v, want InvalidArgument", err)
+	}
+
+	// 赢时退回本金
+	reply, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: held, ClientParams: `{"bets":[]}`, Cheat: "pocket:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gmp := gameModParam(t, reply)
+	if len(gmp.Released) != 1 || !gmp.Released[0].Win || gmp.Released[0].BetType != game.OddEven || gmp.Released[0].WinAmount != 8 || gmp.TotalWin != 8 {
+		t.Errorf("released = %v, totalWin = %d", gmp.Released, gmp.TotalWin)
+	}
+	if _, priv := playerStates(t, reply.PlayerState); len(priv.EnPrison) != 0 || priv.Rounds != 2 {
+		t.Errorf("private state after release = %v", priv)
+	}
+
+	// 已经用过的扣押状态不能再次退回本金, 其他服务(共用密钥)也不接受它
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: held, ClientParams: `{"bets":[]}`, Cheat: "pocket:1"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("reused En Prison state error = %v, want FailedPrecondition", err)
+	}
+	other, err := server.NewServer(server.Config{Table: "t1", Cheats: true, EnPrison: true, StateKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if _, err := other.Play2(ctx, &proto.RequestPlay{PlayerState: held, ClientParams: `{"bets":[]}`}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("En Prison state on another server error = %v, want FailedPrecondition", err)
+	}
+}
+
+// TestEnPrisonReuse 扣押平注的会话只接受最新签发的状态, 账本在重启后从牌局存储重建
+func TestEnPrisonReuse(t *testing.T) {
+	key := make([]byte, server.StateKeySize)
+	cfg := server.Config{Table: "t1", Cheats: true, EnPrison: true, StateKey: key, Store: filepath.Join(t.TempDir(), "rounds.jsonl")}
+	s, err := server.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	red := `{"bets":[{"numbers":[1,3,5,7,9,12,14,16,18,19,21,23,25,27,30,32,34,36],"amount":10}]}`
+	ps, err := s.Initialize(ctx, &proto.RequestInitialize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: red, Cheat: "pocket:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := reply.PlayerState
+
+	// clear 不开局, 返回的状态与之前的状态只能用其中一个
+	cleared, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: held, Command: server.CmdClear})
+	if err != nil {
+		t.Fatalf("clear error = %v", err)
+	}
+	if _, priv := playerStates(t, cleared.PlayerState); len(priv.EnPrison) != 1 {
+		t.Fatalf("clear dropped the held stakes: %v", priv)
+	}
+
+	// 重启后账本从牌局存储重建, 扣押中的状态仍然可用
+	s.Close()
+	if s, err = server.NewServer(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: cleared.PlayerState, ClientParams: `{"bets":[]}`, Cheat: "pocket:2"}); err != nil {
+		t.Fatalf("held state after restart error = %v", err)
+	}
+	for name, ps := range map[string]*proto.PlayerState{"held": held, "cleared": cleared.PlayerState} {
+		if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: `{"bets":[]}`, Cheat: "pocket:2"}); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("reused %s state error = %v, want FailedPrecondition", name, err)
+		}
+	}
+
+	// 失败的局释放会话, 客户端继续使用原来的状态
+	reply, err = s.Play2(ctx, &proto.RequestPlay{PlayerState: ps, ClientParams: red, Cheat: "pocket:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	held = reply.PlayerState
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: held, ClientParams: `{"bets":[{"numbers":[99],"amount":1}]}`}); err == nil {
+		t.Fatal("invalid bet should fail")
+	}
+	if _, err := s.Play2(ctx, &proto.RequestPlay{PlayerState: held, ClientParams: `{"bets":[]}`}); err != nil {
+		t.Errorf("held state after a failed round error = %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+)
+
+// TestRecheck 检查记录的局用当前代码重新结算一致, 篡改赢分后报告不一致
+func TestRecheck(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h, err := server.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	var infos []*proto.RoundInfo
+	for i := 0; i < 20; i++ {
+		reply, err := s.Play2(context.Background(), &proto.RequestPlay{
+			ClientParams: `{"bets":[{"numbers":[17],"amount":10},{"numbers":[1,2,3,4,5,6,7,8,9,10,11,12],"amount":5},{"numbers":[0,1],"amount":2}]}`,
+		})
+		if err != nil {
+			t.Fatalf("Play2() error = %v", err)
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].GetClientData().GetCurGameModParam().UnmarshalTo(&gmp); err != nil {
+			t.Fatal(err)
+		}
+		infos = append(infos, &proto.RoundInfo{
+			Table:         "t1",
+			Round:         reply.Round,
+			WinningNumber: gmp.WinningNumber,
+			Wins:          gmp.Wins,
+			TotalWin:      gmp.TotalWin,
+			RandomNumbers: reply.RandomNumbers,
+		})
+	}
+	for _, info := range infos {
+		if err := h.Append(info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := server.Recheck("history", path, "", 0); err != nil {
+		t.Fatalf("Recheck() error = %v", err)
+	}
+	if err := server.Recheck("history", path, "t2", 0); err == nil {
+		t.Errorf("Recheck() of an unknown table error = nil")
+	}
+
+	// 篡改一局的赢分
+	bad := infos[3]
+	bad.Round = 100
+	bad.Wins[0].WinAmount += 1
+	if err := h.Append(bad); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Recheck("history", path, "t1", 4); err != nil {
+		t.Errorf("Recheck(round 4) error = %v", err)
+	}
+	if err := server.Recheck("history", path, "t1", 0); err == nil {
+		t.Errorf("Recheck() of a tampered round error = nil")
+	}
+}
+
+// TestRecheckSettings 每局记录按记录时的 En Prison 开关和最大下注核对
+func TestRecheckSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rounds.jsonl")
+	s, err := server.NewServer(server.Config{
+		Table:    "t1",
+		Cheats:   true,
+		EnPrison: true,
+		MaxStake: 100,
+		StateKey: make([]byte, server.StateKeySize),
+		RoundLog: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ps, err := s.Initialize(ctx, &proto.RequestInitialize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	odd := `{"bets":[{"numbers":[1,3,5,7,9,11,13,15,17,19,21,23,25,27,29,31,33,35],"amount":8}]}`
+	for i, req := range []*proto.RequestPlay{
+		{ClientParams: odd, Cheat: "pocket:0"},
+		{ClientParams: `{"bets":[]}`, Cheat: "pocket:1"},
+		{ClientParams: `{"bets":[{"numbers":[17],"amount":60}]}`},
+	} {
+		req.PlayerState = ps
+		reply, err := s.Play2(ctx, req)
+		if err != nil {
+			t.Fatalf("round %d: %v", i+1, err)
+		}
+		ps = reply.PlayerState
+	}
+	s.Close()
+
+	if err := server.Recheck("roundLog", path, "", 0); err != nil {
+		t.Fatalf("Recheck() error = %v", err)
+	}
+
+	// 改写记录中的配置
+	rewrite := func(name string, fn func(*server.RoundRecord)) string {
+		var lines []byte
+		err := server.ReadRoundLog(path, func(rec *server.RoundRecord) error {
+			if rec.Settings == nil || !rec.Settings.EnPrison || rec.Settings.MaxStake != 100 {
+				t.Errorf("round %d settings = %+v", rec.Round, rec.Settings)
+			}
+			fn(rec)
+			b, err := json.Marshal(rec)
+			lines = append(append(lines, b...), '\n')
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := filepath.Join(dir, name)
+		if err := os.WriteFile(out, lines, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	off := rewrite("off.jsonl", func(rec *server.RoundRecord) { rec.Settings.EnPrison = false })
+	if err := server.Recheck("roundLog", off, "t1", 1); err == nil {
+		t.Errorf("Recheck() of a stake imprisoned with En Prison off error = nil")
+	}
+	if err := server.Recheck("roundLog", off, "t1", 2); err == nil {
+		t.Errorf("Recheck() of a stake released with En Prison off error = nil")
+	}
+	if err := server.Recheck("roundLog", off, "t1", 3); err != nil {
+		t.Errorf("Recheck() of a round without En Prison stakes error = %v", err)
+	}
+	low := rewrite("low.jsonl", func(rec *server.RoundRecord) { rec.Settings.MaxStake = 50 })
+	if err := server.Recheck("roundLog", low, "t1", 3); err == nil {
+		t.Errorf("Recheck() of a stake above the recorded max stake error = nil")
+	}
+	// 旧记录没有配置, 不检查
+	old := rewrite("old.jsonl", func(rec *server.RoundRecord) { rec.Settings = nil })
+	if err := server.Recheck("roundLog", old, "", 0); err != nil {
+		t.Errorf("Recheck() of records without settings error = %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/fair"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+)
+
+// TestReplayRoundLog 确定性RNG下记录的每局可以重放, 修改过的记录被发现, fair 局跳过
+func TestReplayRoundLog(t *testing.T) {
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{7}, server.StateKeySize)
+	path := filepath.Join(dir, "rounds.jsonl")
+	s, err := server.NewServer(server.Config{
+		Table:    "t1",
+		Seed:     []byte("replay seed"),
+		RoundLog: path,
+		EnPrison: true,
+		StateKey: key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ps, err := s.Initialize(ctx, &proto.RequestInitialize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, req := range []*proto.RequestPlay{
+		{ClientParams: `{"bets":[{"numbers":[17],"amount":10},{"numbers":[1,3,5,7,9,11,13,15,17,19,21,23,25,27,29,31,33,35],"amount":4}]}`},
+		{Command: server.CmdRebet},
+		{Command: server.CmdDouble},
+		{ClientParams: `{"bets":[]}`, RoundId: "r4"},
+	} {
+		req.PlayerState = ps
+		reply, err := s.Play2(ctx, req)
+		if err != nil {
+			t.Fatalf("round %d: %v", i+1, err)
+		}
+		ps = reply.PlayerState
+	}
+	s.Close()
+
+	if err := server.Replay(path, key); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	// 不知道密钥时不检查签名, 结果照常比较
+	if err := server.Replay(path, nil); err != nil {
+		t.Fatalf("Replay() without state key error = %v", err)
+	}
+
+	// 改动一局的结算
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(b, []byte("\n"))
+	lines[1] = bytes.Replace(lines[1], []byte(`"payout":35`), []byte(`"payout":36`), 1)
+	tampered := filepath.Join(dir, "tampered.jsonl")
+	if err := os.WriteFile(tampered, bytes.Join(lines, nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Replay(tampered, key); err == nil {
+		t.Errorf("Replay() of a tampered round log error = nil")
+	}
+
+	// fair 局的获胜数字不来自种子, 跳过而不是报告不一致
+	m, err := fair.NewManager(filepath.Join(dir, "seeds.json"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fairPath := filepath.Join(dir, "fair.jsonl")
+	fs, err := server.NewServer(server.Config{Table: "t1", Seed: []byte("replay seed"), RoundLog: fairPath, Fair: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := fs.Play2(ctx, &proto.RequestPlay{ClientParams: `{"bets":[{"numbers":[17],"amount":1}],"clientSeed":"lucky"}`}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs.Close()
+	if err := server.Replay(fairPath, nil); err != nil {
+		t.Errorf("Replay() of fair rounds error = %v", err)
+	}
+}
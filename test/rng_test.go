@@ -0,0 +1,529 @@
+package test
+
+import (
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/rng"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestGetRngsRange 检查RNG服务的原始输出和服务端缩放
+func TestGetRngsRange(t *testing.T) {
+	s, err := rng.NewRng()
+	if err != nil {
+		t.Fatalf("NewRng() error = %v", err)
+	}
+
+	raw, err := s.GetRngs(context.Background(), &proto.RequestRngs{Nums: 1000})
+	if err != nil {
+		t.Fatalf("GetRngs() error = %v", err)
+	}
+	if raw.Bits != rng.RngBits || raw.Range != 0 || len(raw.Rngs) != 1000 {
+		t.Fatalf("GetRngs() bits = %d, range = %d, len = %d", raw.Bits, raw.Range, len(raw.Rngs))
+	}
+	high := false
+	for _, v := range raw.Rngs {
+		if v >= 1<<24 {
+			high = true
+		}
+	}
+	if !high {
+		t.Errorf("GetRngs() raw values never use the high bits")
+	}
+
+	scaled, err := s.GetRngs(context.Background(), &proto.RequestRngs{Nums: 1000, Range: 37})
+	if err != nil {
+		t.Fatalf("GetRngs() error = %v", err)
+	}
+	if scaled.Range != 37 || len(scaled.Rngs) != 1000 {
+		t.Fatalf("GetRngs() range = %d, len = %d", scaled.Range, len(scaled.Rngs))
+	}
+	for _, v := range scaled.Rngs {
+		if v >= 37 {
+			t.Fatalf("GetRngs() value %d out of range", v)
+		}
+	}
+
+	if _, err := s.GetRngs(context.Background(), &proto.RequestRngs{Range: -1}); err == nil {
+		t.Errorf("GetRngs() should reject a negative range")
+	}
+}
+
+// TestScaleUniform 检查拒绝区间的边界
+func TestScaleUniform(t *testing.T) {
+	limit := uint32((uint64(1) << 32) - (uint64(1)<<32)%37)
+	if v, ok := rng.ScaleUniform(limit-1, 37); !ok || v != (limit-1)%37 {
+		t.Errorf("ScaleUniform(limit-1) = %d, %v", v, ok)
+	}
+	if _, ok := rng.ScaleUniform(limit, 37); ok {
+		t.Errorf("ScaleUniform(limit) should be rejected")
+	}
+}
+
+// TestDRBGDeterministic 相同种子得到相同序列, 不同局的种子互不相同
+func TestDRBGDeterministic(t *testing.T) {
+	seedA := rng.RoundSeed([]byte("master"), "t1", 1)
+	seedB := rng.RoundSeed([]byte("master"), "t1", 2)
+
+	a, _ := rng.NewDRBG(seedA).GetRandomNumbers(100, 37)
+	b, _ := rng.NewDRBG(seedA).GetRandomNumbers(100, 37)
+	c, _ := rng.NewDRBG(seedB).GetRandomNumbers(100, 37)
+
+	same := true
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("DRBG output %d differs for the same seed", i)
+		}
+		if a[i] != c[i] {
+			same = false
+		}
+	}
+	if same {
+		t.Errorf("DRBG output is identical for different round seeds")
+	}
+}
+
+// TestHealthTester 检查重复计数检测和自适应比例检测
+func TestHealthTester(t *testing.T) {
+	ht := rng.NewHealthTester(rng.EntropyPerSample)
+	buf := make([]byte, 4096)
+	if _, err := crand.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.Feed(buf); err != nil {
+		t.Fatalf("Feed(random) error = %v", err)
+	}
+
+	stuck := make([]byte, 64)
+	if err := ht.Feed(stuck); err == nil {
+		t.Fatalf("Feed(stuck) passed RCT")
+	}
+	if st := ht.Status(); st.OK || st.RctFailures != 1 {
+		t.Errorf("Status() ok = %v, rctFailures = %d", st.OK, st.RctFailures)
+	}
+	// 失败后锁定, 正常数据也不再通过
+	if err := ht.Feed(buf); err == nil {
+		t.Errorf("Feed() after failure error = nil")
+	}
+
+	// 每隔一个字节重复同一个值, 不会触发RCT, 但会触发APT
+	apt := rng.NewHealthTester(rng.EntropyPerSample)
+	biased := make([]byte, rng.AptWindow)
+	for i := range biased {
+		if i%2 == 0 {
+			biased[i] = 0xAA
+		} else {
+			biased[i] = byte(i)
+		}
+	}
+	if err := apt.Feed(biased); err == nil {
+		t.Fatalf("Feed(biased) passed APT")
+	}
+	if st := apt.Status(); st.AptFailures != 1 {
+		t.Errorf("Status() aptFailures = %d", st.AptFailures)
+	}
+
+	// 重置后重新检测, 失败次数保留
+	ht.Reset()
+	if err := ht.Feed(buf); err != nil {
+		t.Fatalf("Feed(random) after Reset() error = %v", err)
+	}
+	if st := ht.Status(); !st.OK || st.Err != nil || st.RctFailures != 1 {
+		t.Errorf("Status() after Reset() = %+v", st)
+	}
+	if err := ht.Feed(stuck); err == nil {
+		t.Errorf("Feed(stuck) after Reset() passed RCT")
+	}
+}
+
+// TestGenerators 检查每种发生器都能输出并在应答中报告算法
+func TestGenerators(t *testing.T) {
+	for _, alg := range rng.Algorithms {
+		s, err := rng.NewRngWithConfig(rng.Config{Algorithm: alg, ReseedInterval: 2})
+		if err != nil {
+			t.Fatalf("NewRngWithConfig(%s) error = %v", alg, err)
+		}
+		seen := map[uint32]bool{}
+		for i := 0; i < 4; i++ {
+			reply, err := s.GetRngs(context.Background(), &proto.RequestRngs{Nums: 100, Range: 37})
+			if err != nil {
+				t.Fatalf("%s: GetRngs() error = %v", alg, err)
+			}
+			if reply.Algorithm != alg {
+				t.Errorf("%s: GetRngs() algorithm = %q", alg, reply.Algorithm)
+			}
+			for _, v := range reply.Rngs {
+				if v >= 37 {
+					t.Fatalf("%s: GetRngs() value %d out of range", alg, v)
+				}
+				seen[v] = true
+			}
+		}
+		if len(seen) < 30 {
+			t.Errorf("%s: only %d distinct pockets in 400 draws", alg, len(seen))
+		}
+
+		h, err := s.Health(context.Background(), &proto.RequestHealth{})
+		if err != nil {
+			t.Fatalf("%s: Health() error = %v", alg, err)
+		}
+		if !h.Ok || h.Algorithm != alg {
+			t.Errorf("%s: Health() ok = %v, algorithm = %q", alg, h.Ok, h.Algorithm)
+		}
+		if alg != rng.AlgCrypto && h.Reseeds < 2 {
+			t.Errorf("%s: Health() reseeds = %d, want periodic reseeding", alg, h.Reseeds)
+		}
+	}
+
+	if _, err := rng.NewRngWithConfig(rng.Config{Algorithm: "mt19937"}); err == nil {
+		t.Errorf("NewRngWithConfig(mt19937) error = nil")
+	}
+}
+
+// TestStreams 检查每个游戏代码和桌子使用独立的流并单独统计
+func TestStreams(t *testing.T) {
+	s, err := rng.NewRngWithConfig(rng.Config{Algorithm: rng.AlgHMACDRBG})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, req := range []*proto.RequestRngs{
+		{Nums: 370, Range: 37, Gamecode: "roulette", Table: "t1"},
+		{Nums: 370, Range: 37, Gamecode: "roulette", Table: "t1"},
+		{Nums: 52, Range: 52, Gamecode: "roulette", Table: "t2"},
+		{Nums: 10, Gamecode: "cards"},
+	} {
+		if _, err := s.GetRngs(ctx, req); err != nil {
+			t.Fatalf("GetRngs(%v) error = %v", req, err)
+		}
+	}
+
+	admin := rng.NewAdmin(s)
+	reply, err := admin.Streams(ctx, &proto.RequestStreams{Gamecode: "roulette", Counts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Streams) != 2 {
+		t.Fatalf("Streams(roulette) = %d streams, want 2", len(reply.Streams))
+	}
+	t1, t2 := reply.Streams[0], reply.Streams[1]
+	if t1.Table != "t1" || t1.Requests != 2 || t1.Values != 740 || t1.Algorithm != rng.AlgHMACDRBG {
+		t.Errorf("t1 = %v", t1)
+	}
+	if len(t1.Ranges) != 1 || t1.Ranges[0].Range != 37 || t1.Ranges[0].Total != 740 || len(t1.Ranges[0].Counts) != 37 || t1.Ranges[0].Df != 36 {
+		t.Errorf("t1 ranges = %v", t1.Ranges)
+	}
+	if t2.Table != "t2" || t2.Requests != 1 || t2.Values != 52 {
+		t.Errorf("t2 = %v", t2)
+	}
+	if t1.Reseeds == 0 || t2.Reseeds == 0 {
+		t.Errorf("streams were not seeded separately: %d, %d", t1.Reseeds, t2.Reseeds)
+	}
+
+	if h, err := admin.ResetHealth(ctx, &proto.RequestResetHealth{}); err != nil || !h.Ok || h.Samples == 0 {
+		t.Errorf("ResetHealth() = %v, %v", h, err)
+	}
+
+	all, _ := admin.Streams(ctx, &proto.RequestStreams{})
+	// 默认流 + roulette/t1 + roulette/t2 + cards
+	if len(all.Streams) != 4 {
+		t.Errorf("Streams() = %d streams, want 4", len(all.Streams))
+	}
+	for _, st := range all.Streams {
+		for _, r := range st.Ranges {
+			if r.Counts != nil {
+				t.Errorf("Streams() without counts returned counts for %s/%s", st.Gamecode, st.Table)
+			}
+		}
+	}
+}
+
+// TestStreamEviction 流数量达到上限时回收空闲的流, 没有空闲的流时拒绝新的桌子
+func TestStreamEviction(t *testing.T) {
+	const maxStreams = 1024
+	ctx := context.Background()
+	fill := func(s *rng.Rng, prefix string) {
+		t.Helper()
+		// 默认流已经占用一个位置
+		for i := 1; i < maxStreams; i++ {
+			if _, err := s.GetRngs(ctx, &proto.RequestRngs{Gamecode: "roulette", Table: fmt.Sprintf("%s%d", prefix, i)}); err != nil {
+				t.Fatalf("GetRngs(%s%d) error = %v", prefix, i, err)
+			}
+		}
+	}
+
+	busy, err := rng.NewRngWithConfig(rng.Config{Algorithm: rng.AlgHMACDRBG})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(busy, "t")
+	if _, err := busy.GetRngs(ctx, &proto.RequestRngs{Gamecode: "roulette", Table: "new"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetRngs() over the limit error = %v, want ResourceExhausted", err)
+	}
+
+	idle, err := rng.NewRngWithConfig(rng.Config{Algorithm: rng.AlgHMACDRBG, StreamIdle: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(idle, "t")
+	time.Sleep(100 * time.Millisecond)
+	if _, err := idle.GetRngs(ctx, &proto.RequestRngs{Gamecode: "roulette", Table: "new"}); err != nil {
+		t.Fatalf("GetRngs() after idle streams error = %v", err)
+	}
+	reply, _ := rng.NewAdmin(idle).Streams(ctx, &proto.RequestStreams{})
+	if len(reply.Streams) != 1 || reply.Streams[0].Table != "new" {
+		t.Errorf("Streams() after eviction = %d streams", len(reply.Streams))
+	}
+}
+
+// TestPrefetchPool 检查本地池命中和后台补充
+func TestPrefetchPool(t *testing.T) {
+	local, err := rng.NewLocalRNGClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := local.Prefetch(rng.PoolConfig{Low: 20, High: 100, Timeout: time.Second})
+	defer c.Close()
+
+	waitSize := func(min int) rng.PoolStats {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			st := c.PoolStats()
+			if st.Size >= min || time.Now().After(deadline) {
+				return st
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	if st := waitSize(100); st.Size != 100 {
+		t.Fatalf("pool size after start = %d, want 100", st.Size)
+	}
+
+	for i := 0; i < 90; i++ {
+		v, err := c.GetRandomNumber(37)
+		if err != nil {
+			t.Fatalf("GetRandomNumber() error = %v", err)
+		}
+		if _, ok := rng.ScaleUniform(v, 37); !ok {
+			t.Fatalf("GetRandomNumber() = %d, not in the unbiased zone", v)
+		}
+	}
+	// 低于低水位后补充回高水位
+	st := waitSize(80)
+	if st.Hits < 90 || st.Refills < 2 || st.Size < 80 {
+		t.Errorf("PoolStats() = %+v", st)
+	}
+
+	// 池不够时同步请求
+	if _, err := c.GetRawNumbers(1000); err != nil {
+		t.Fatalf("GetRawNumbers(1000) error = %v", err)
+	}
+	if c.PoolStats().Misses != 1 {
+		t.Errorf("PoolStats().Misses = %d, want 1", c.PoolStats().Misses)
+	}
+}
+
+// TestFailover 检查两种策略在主服务不可用时的行为
+func TestFailover(t *testing.T) {
+	live, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	liveRng, err := rng.NewRng()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto.RegisterRngServer(gs, liveRng)
+	go gs.Serve(live)
+	defer gs.Stop()
+
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadAddr := dead.Addr().String()
+	dead.Close()
+
+	addrs := []string{deadAddr, live.Addr().String()}
+
+	fo, err := rng.NewRNGClientWithConfig(rng.ClientConfig{Addrs: addrs, Policy: rng.PolicyFailover, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fo.Close()
+	if _, err := fo.GetRandomNumber(37); err != nil {
+		t.Fatalf("failover: GetRandomNumber() error = %v", err)
+	}
+	eps := fo.Endpoints()
+	if eps[0].Healthy || eps[0].Active || !eps[1].Healthy || !eps[1].Active {
+		t.Errorf("failover: Endpoints() = %+v", eps)
+	}
+
+	fc, err := rng.NewRNGClientWithConfig(rng.ClientConfig{Addrs: addrs, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fc.Close()
+	if _, err := fc.GetRandomNumber(37); status.Code(err) != codes.Unavailable {
+		t.Errorf("failclosed: GetRandomNumber() error = %v, want Unavailable", err)
+	}
+
+	if _, err := rng.NewRNGClientWithConfig(rng.ClientConfig{Addrs: addrs, Policy: "random"}); err == nil {
+		t.Errorf("NewRNGClientWithConfig(policy random) error = nil")
+	}
+}
+
+// TestAuditLog 检查每局消耗的随机数可以按局号查回
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	al, err := rng.OpenAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want [][]rng.Draw
+	for round := uint64(1); round <= 3; round++ {
+		rec := rng.NewRecorder(rng.NewDRBG([]byte{byte(round)}))
+		g := game.NewRoulette(rec)
+		n, err := g.Spin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		draws := rec.Draws()
+		if len(draws) != 1 || draws[0].Range != game.NumberCount || int(draws[0].Result) != n {
+			t.Fatalf("round %d draws = %+v, spin = %d", round, draws, n)
+		}
+		if err := al.Append(rng.GameCode, "t1", round, "seeded", draws); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, draws)
+	}
+	al.Close()
+
+	got, err := rng.QueryAudit(path, "t1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Draw, want[1][0]) || got[0].Round != 2 || got[0].Gamecode != rng.GameCode {
+		t.Errorf("QueryAudit(t1, 2) = %+v, want %+v", got, want[1])
+	}
+	if got, _ := rng.QueryAudit(path, "t2", 2); len(got) != 0 {
+		t.Errorf("QueryAudit(t2, 2) = %+v, want none", got)
+	}
+}
+
+// TestStreamRngs 检查大批量请求走流式接口并分块返回
+func TestStreamRngs(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := rng.NewRng()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	proto.RegisterRngServer(gs, srv)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	c, err := rng.NewRNGClient(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	bulk := c.Stream("bulk", "")
+
+	n := 2*rng.DefaultStreamChunk + 100
+	vals, err := bulk.GetRawNumbers(int32(n))
+	if err != nil {
+		t.Fatalf("GetRawNumbers(%d) error = %v", n, err)
+	}
+	if len(vals) != n {
+		t.Fatalf("GetRawNumbers(%d) returned %d values", n, len(vals))
+	}
+
+	// 每块是一次生成请求, 共三块
+	reply, _ := rng.NewAdmin(srv).Streams(context.Background(), &proto.RequestStreams{Gamecode: "bulk"})
+	if len(reply.Streams) != 1 || reply.Streams[0].Requests != 3 || reply.Streams[0].Values != uint64(n) {
+		t.Errorf("bulk stream stats = %v", reply.Streams)
+	}
+
+	lc, err := rng.NewLocalRNGClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := lc.GetRandomNumbers(int32(n), 37)
+	if err != nil || len(local) != n {
+		t.Fatalf("local GetRandomNumbers(%d) = %d values, error = %v", n, len(local), err)
+	}
+}
+
+// rawSeq 按顺序返回固定的原始随机数
+type rawSeq struct{ vals []uint32 }
+
+func (s *rawSeq) GetRawNumbers(nums int32) ([]uint32, error) {
+	out := s.vals[:nums]
+	s.vals = s.vals[nums:]
+	return out, nil
+}
+
+func (s *rawSeq) GetRandomNumber(r int) (uint32, error) { panic("unused") }
+
+func (s *rawSeq) GetRandomNumbers(nums int32, r int) ([]uint32, error) { panic("unused") }
+
+func (s *rawSeq) ScalingRandom(rngs []uint32, r int) (uint32, []uint32, error) { panic("unused") }
+
+// TestRngInfo 检查 ReplyPlay 中的随机数足以重新算出获胜数字
+func TestRngInfo(t *testing.T) {
+	rec := rng.NewRecorder(&rawSeq{vals: []uint32{0xFFFFFFFF, 40}})
+	if n, err := game.NewRoulette(rec).Spin(); err != nil || n != 3 {
+		t.Fatalf("Spin() = %d, %v, want 3", n, err)
+	}
+	want := []rng.Draw{{Raw: 40, Range: 37, Result: 3, Rejected: []uint32{0xFFFFFFFF}}}
+	if got := rec.Draws(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Draws() = %+v, want %+v", got, want)
+	}
+
+	s := server.NewRouletteServer(nil, nil)
+	for i := 0; i < 20; i++ {
+		reply, err := s.Play2(context.Background(), &proto.RequestPlay{
+			ClientParams: `{"bets":[{"numbers":[17],"amount":1}]}`,
+		})
+		if err != nil {
+			t.Fatalf("Play2() error = %v", err)
+		}
+		var gmp proto.GameModParam
+		if err := reply.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		infos := reply.RandomNumbers
+		if len(infos) == 0 || infos[0].Range != game.NumberCount || int32(infos[0].Raw%uint32(infos[0].Range)) != gmp.WinningNumber {
+			t.Fatalf("RandomNumbers = %v, winning number %d", infos, gmp.WinningNumber)
+		}
+		for _, info := range infos {
+			v, ok := rng.ScaleUniform(info.Raw, uint32(info.Range))
+			if !ok || int32(v) != info.Value || info.Bits != 32 || info.Source != "local" {
+				t.Errorf("RngInfo %v does not reconstruct", info)
+			}
+			for _, r := range info.Rejected {
+				if _, ok := rng.ScaleUniform(r, uint32(info.Range)); ok {
+					t.Errorf("RngInfo %v: rejected value %d is acceptable", info, r)
+				}
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"fmt"
 	"math"
+	"net"
 	"testing"
 
 	"gitee.com/heartfun/rouletteserv/game"
@@ -95,3 +96,20 @@ func TestSimulation(t *testing.T) {
 		})
 	}
 }
+
+// TestCalculateRTPError RNG服务不可用时返回错误, 不返回部分结果
+func TestCalculateRTPError(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	if rtp, err := game.CalculateRTP(100000, addr); err == nil {
+		t.Errorf("CalculateRTP() with a dead RNG = %v, want an error", rtp)
+	}
+	if _, err := game.CalculateRTP(5, ""); err == nil {
+		t.Errorf("CalculateRTP() with fewer rounds than bet types error = nil")
+	}
+}
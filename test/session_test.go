@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/proto"
+	"gitee.com/heartfun/rouletteserv/server"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// cannedServer 按 clientParams 返回记录的结果, 局号与记录不同
+type cannedServer struct {
+	proto.UnimplementedGameLogicServer
+	replies map[string]*proto.ReplyPlay
+}
+
+func (s *cannedServer) Play2(ctx context.Context, req *proto.RequestPlay) (*proto.ReplyPlay, error) {
+	reply, ok := s.replies[req.ClientParams]
+	if !ok {
+		return nil, fmt.Errorf("unknown request")
+	}
+	reply = gproto.Clone(reply).(*proto.ReplyPlay)
+	reply.Round += 100
+	reply.RoundId = req.RoundId
+	return reply, nil
+}
+
+// TestReplaySession 检查回放比较记录的结果, 忽略局号和 roundId, 报告不同的结果和失败的请求
+func TestReplaySession(t *testing.T) {
+	s := server.NewRouletteServer(nil, nil)
+	canned := &cannedServer{replies: make(map[string]*proto.ReplyPlay)}
+	var lines []string
+	for i := 1; i <= 3; i++ {
+		req := &proto.RequestPlay{
+			ClientParams: fmt.Sprintf(`{"bets":[{"numbers":[%d],"amount":10},{"numbers":[1,2,3],"amount":%d}]}`, i, i),
+			RoundId:      fmt.Sprintf("r%d", i),
+		}
+		reply, err := s.Play2(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		canned.replies[req.ClientParams] = reply
+		reqJSON, _ := protojson.Marshal(req)
+		replyJSON, _ := protojson.Marshal(reply)
+		lines = append(lines, fmt.Sprintf(`{"request":%s,"reply":%s}`, reqJSON, replyJSON))
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	proto.RegisterGameLogicServer(gs, canned)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	dir := t.TempDir()
+	replay := func(lines ...string) error {
+		path := filepath.Join(dir, "requests.jsonl")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return server.ReplaySession(server.SessionConfig{Addr: lis.Addr().String(), Path: path})
+	}
+
+	if err := replay(lines...); err != nil {
+		t.Fatalf("ReplaySession() error = %v", err)
+	}
+
+	// 服务端的结算与记录不同
+	var gmp proto.GameModParam
+	first := canned.replies[`{"bets":[{"numbers":[1],"amount":10},{"numbers":[1,2,3],"amount":1}]}`]
+	first.Results[0].ClientData.CurGameModParam.UnmarshalTo(&gmp)
+	gmp.TotalWin++
+	first.Results[0].ClientData.CurGameModParam.MarshalFrom(&gmp)
+	if err := replay(lines...); err == nil || !strings.Contains(err.Error(), "1 of 3 replies differ") {
+		t.Errorf("ReplaySession() with a changed result error = %v", err)
+	}
+
+	// 单独的请求只发送不比较, 失败的请求报告为错误
+	if err := replay(lines[1], `{"clientParams":"{}"}`); err == nil || !strings.Contains(err.Error(), "1 of 2 requests failed") {
+		t.Errorf("ReplaySession() with a failing request error = %v", err)
+	}
+	if err := replay(`{"request_id":"x"}`); err == nil {
+		t.Errorf("ReplaySession() of a non-capture file error = nil")
+	}
+}
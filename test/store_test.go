@@ -0,0 +1,71 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitee.com/heartfun/rouletteserv/store"
+)
+
+// TestFileStore 检查重新打开后能恢复没有结束的局, 写了一半的记录被丢弃
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rounds.jsonl")
+	st, err := store.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for round := uint64(1); round <= 4; round++ {
+		if err := st.Begin(&store.Round{Table: "t1", Round: round, RoundID: "id", Request: []byte(`{"clientParams":"{}"}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(st.Outcome("t1", 1, []byte(`{"round":"1"}`)))
+	must(st.Settle("t1", 1, nil))
+	must(st.Void("t1", 2, "paused"))
+	must(st.Outcome("t1", 3, []byte(`{"round":"3"}`)))
+	if err := st.Settle("t1", 1, nil); err == nil {
+		t.Errorf("Settle() of a settled round error = nil")
+	}
+	st.Close()
+
+	// 模拟崩溃时写了一半的记录
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"type":"settle","table":"t1","rou`)
+	f.Close()
+
+	st, err = store.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() after crash error = %v", err)
+	}
+	defer st.Close()
+	open := st.Unfinished("t1")
+	if len(open) != 2 || open[0].Round != 3 || open[0].State != store.StateOutcome || string(open[0].Reply) != `{"round":"3"}` ||
+		open[1].Round != 4 || open[1].State != store.StateBegun {
+		t.Fatalf("Unfinished() = %+v", open)
+	}
+	if last := st.Last("t1"); last != 4 {
+		t.Errorf("Last() = %d, want 4", last)
+	}
+	if len(st.Unfinished("t2")) != 0 || st.Last("t2") != 0 {
+		t.Errorf("t2 has rounds")
+	}
+
+	// 恢复后可以继续写入
+	must(st.Settle("t1", 3, nil))
+	recent, err := st.Recent("t1", time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 3 || recent[2].Round != 3 || recent[2].State != store.StateSettled || string(recent[2].Reply) != `{"round":"3"}` ||
+		recent[1].State != store.StateVoid || recent[1].Reason != "paused" {
+		t.Errorf("Recent() = %+v", recent)
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gitee.com/heartfun/rouletteserv/game"
+	"gitee.com/heartfun/rouletteserv/rng"
+)
+
+// TestAnimate 检查动画参数与获胜数字在轮盘上的位置一致
+func TestAnimate(t *testing.T) {
+	roulette := game.NewRoulette(nil)
+
+	for n := 0; n < game.NumberCount; n++ {
+		anim, err := roulette.Animate(n)
+		if err != nil {
+			t.Fatalf("Animate(%d) error = %v", n, err)
+		}
+
+		idx, err := game.PocketIndex(n)
+		if err != nil {
+			t.Fatalf("PocketIndex(%d) error = %v", n, err)
+		}
+		if anim.PocketIndex != idx {
+			t.Errorf("Animate(%d) pocket index = %d, want %d", n, anim.PocketIndex, idx)
+		}
+
+		want := math.Mod(anim.RotorStartAngle+anim.RotorSpeed*anim.Duration+float64(idx)*360/game.NumberCount, 360)
+		if math.Abs(anim.LandingAngle-want) > 1e-9 {
+			t.Errorf("Animate(%d) landing angle = %.3f, want %.3f", n, anim.LandingAngle, want)
+		}
+		if anim.BallRevolutions < 8 || anim.BallRevolutions > 12 {
+			t.Errorf("Animate(%d) ball revolutions = %d", n, anim.BallRevolutions)
+		}
+	}
+
+	if _, err := roulette.Animate(game.NumberCount); err == nil {
+		t.Errorf("Animate(%d) should fail", game.NumberCount)
+	}
+}
+
+// countingSeq 记录 GetRawNumbers 的调用
+type countingSeq struct {
+	rawSeq
+	calls []int32
+}
+
+func (s *countingSeq) GetRawNumbers(nums int32) ([]uint32, error) {
+	s.calls = append(s.calls, nums)
+	return s.rawSeq.GetRawNumbers(nums)
+}
+
+// TestAnimateBatch 检查动画参数一次取出, 被拒绝的值才补取, 记录与逐个取数相同
+func TestAnimateBatch(t *testing.T) {
+	src := &countingSeq{rawSeq: rawSeq{vals: []uint32{10, 20, 0xFFFFFFFF, 30, 4, 50}}}
+	rec := rng.NewRecorder(src)
+	anim, err := game.NewRoulette(rec).Animate(0)
+	if err != nil {
+		t.Fatalf("Animate(0) error = %v", err)
+	}
+	if !reflect.DeepEqual(src.calls, []int32{5, 1}) {
+		t.Errorf("GetRawNumbers calls = %v, want [5 1]", src.calls)
+	}
+	want := []rng.Draw{
+		{Raw: 10, Range: 3600, Result: 10},
+		{Raw: 20, Range: 201, Result: 20},
+		{Raw: 30, Range: 31, Result: 30, Rejected: []uint32{0xFFFFFFFF}},
+		{Raw: 4, Range: 5, Result: 4},
+		{Raw: 50, Range: 3600, Result: 50},
+	}
+	if got := rec.Draws(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Draws() = %+v, want %+v", got, want)
+	}
+	if anim.RotorStartAngle != 1 || anim.RotorSpeed != 22 || anim.Duration != 10 || anim.BallRevolutions != 12 || anim.BallStartAngle != 5 {
+		t.Errorf("Animate(0) = %+v", anim)
+	}
+}